@@ -0,0 +1,127 @@
+package ghosttohugo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// hugoURL returns the path a post or page is served from in the Hugo site
+func (c Converter) hugoURL(p post) string {
+	if p.isPage() {
+		return "/" + p.Slug + "/"
+	}
+	if col, ok := c.collection(p); ok && col.permalink != "" {
+		return c.expandPermalink(col.permalink, p)
+	}
+	return "/post/" + p.Slug + "/"
+}
+
+// ghostPath returns the path Ghost served the post from, following the
+// collection it belongs to in routes.yaml, or else the permalink pattern
+// given to the converter or the permalinks setting of the export. Pages were
+// always served from their slug.
+func (c Converter) ghostPath(p post) string {
+	if p.isPage() {
+		return "/" + p.Slug + "/"
+	}
+	if col, ok := c.collection(p); ok && col.permalink != "" {
+		return c.expandPermalink(col.permalink, p)
+	}
+
+	pattern := c.permalink
+	if pattern == "" {
+		pattern = c.info.settings["permalinks"]
+	}
+	if pattern == "" {
+		pattern = "/:slug/"
+	}
+	return c.expandPermalink(pattern, p)
+}
+
+// expandPermalink fills in a Ghost permalink pattern for the post. Both the
+// :name form of the permalinks setting and the {name} form of routes.yaml
+// are supported. Dates are taken in the time zone of the site, as Ghost did.
+func (c Converter) expandPermalink(pattern string, p post) string {
+	date := p.Published
+	if date.IsZero() {
+		date = p.Created
+	}
+	if c.location != nil {
+		date = date.In(c.location)
+	}
+	var id string
+	if err := json.Unmarshal(p.ID, &id); err != nil {
+		id = string(p.ID)
+	}
+	author := "undefined"
+	if len(p.Authors) > 0 {
+		author = p.Authors[0]
+	}
+	tag := "all"
+	if len(p.Tags) > 0 {
+		tag = p.Tags[0]
+	}
+
+	var pairs []string
+	for name, value := range map[string]string{
+		"slug":           p.Slug,
+		"year":           fmt.Sprintf("%04d", date.Year()),
+		"month":          fmt.Sprintf("%02d", date.Month()),
+		"day":            fmt.Sprintf("%02d", date.Day()),
+		"id":             id,
+		"primary_author": author,
+		"author":         author,
+		"primary_tag":    tag,
+	} {
+		pairs = append(pairs, ":"+name, value, "{"+name+"}", value)
+	}
+	path := strings.NewReplacer(pairs...).Replace(pattern)
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// pageURL is where a post or page was served from in Ghost, and where it is
+// served from in the Hugo site
+type pageURL struct {
+	slug, ghost, hugo string
+}
+
+// indexPosts records the URLs of every post of the export, so links to them
+// can be rewritten before the posts are converted.
+func (c *Converter) indexPosts() {
+	c.pages = nil
+	for _, ref := range c.info.Data.Posts {
+		p := ref.post()
+		c.populatePost(&p)
+		c.pages = append(c.pages, pageURL{
+			slug:  p.Slug,
+			ghost: c.ghostPath(p),
+			hugo:  c.hugoURL(p),
+		})
+	}
+}
+
+// postAliases returns the aliases that keep the Ghost URLs of a post
+// working. Drafts were never published, so they get none.
+func (c Converter) postAliases(p post) []string {
+	if p.isDraft() {
+		return nil
+	}
+
+	var aliases []string
+	if path := c.ghostPath(p); path != c.hugoURL(p) {
+		aliases = append(aliases, path)
+	}
+	if c.amp && !p.isPage() {
+		path := c.ghostPath(p)
+		if !strings.HasSuffix(path, "/") {
+			path += "/"
+		}
+		aliases = append(aliases, path+"amp/")
+	}
+	return aliases
+}
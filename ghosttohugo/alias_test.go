@@ -0,0 +1,159 @@
+package ghosttohugo
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+)
+
+func TestConverter_ghostPath(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := post{
+		ID:        json.RawMessage(`"5f1a"`),
+		Slug:      "hello",
+		Status:    "published",
+		Published: time.Date(2020, 3, 4, 15, 6, 7, 0, time.UTC),
+		Authors:   []string{"jo"},
+		Tags:      []string{"news", "travel"},
+	}
+
+	tests := []struct {
+		name      string
+		permalink string
+		setting   string
+		p         post
+		want      string
+	}{
+		{"default", "", "", p, "/hello/"},
+		{"setting", "", "/:year/:month/:day/:slug/", p, "/2020/03/04/hello/"},
+		{"given", "/blog/:slug", "/:year/:slug/", p, "/blog/hello"},
+		{"id", ":id/:slug/", "", p, "/5f1a/hello/"},
+		{"author_tag", "/:primary_author/:primary_tag/:slug/", "", p, "/jo/news/hello/"},
+		{"no_tag", "/:primary_tag/:slug/", "", post{Slug: "hello"}, "/all/hello/"},
+		{
+			"site_time_zone",
+			"/:year/:month/:day/:slug/",
+			"",
+			post{Slug: "s", Published: time.Date(2021, 4, 1, 2, 0, 0, 0, time.UTC)},
+			"/2021/03/31/s/",
+		},
+		{
+			"page",
+			"",
+			"/:year/:slug/",
+			post{Slug: "about", Page: json.RawMessage("true")},
+			"/about/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{
+				location:  newYork,
+				permalink: tt.permalink,
+				info:      info{settings: map[string]string{"permalinks": tt.setting}},
+			}
+			if got := c.ghostPath(tt.p); got != tt.want {
+				t.Errorf("Converter.ghostPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_postAliases(t *testing.T) {
+	tests := []struct {
+		name      string
+		permalink string
+		amp       bool
+		p         post
+		want      []string
+	}{
+		{"post", "", false, post{Slug: "a", Status: "published"}, []string{"/a/"}},
+		{"draft", "", false, post{Slug: "a", Status: "draft"}, nil},
+		{
+			"same_url",
+			"/post/:slug/",
+			false,
+			post{Slug: "a", Status: "published"},
+			nil,
+		},
+		{
+			"page",
+			"",
+			true,
+			post{Slug: "a", Status: "published", Page: json.RawMessage("true")},
+			nil,
+		},
+		{
+			"amp",
+			"/:slug",
+			true,
+			post{Slug: "a", Status: "published"},
+			[]string{"/a", "/a/amp/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{permalink: tt.permalink, amp: tt.amp}
+			if got := c.postAliases(tt.p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Converter.postAliases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_archiveAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		archives bool
+	}{
+		{"on", true},
+		{"off", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{
+				path:     t.TempDir(),
+				kind:     metadecoders.TOML,
+				archives: tt.archives,
+				info: info{Data: data{
+					Users: []user{{Name: "Jo", Slug: "jo"}},
+					Tags:  []tag{{Name: "News", Slug: "news"}},
+				}},
+			}
+			if err := c.createSite(); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.writeAuthors(); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.writeTags(); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, page := range []struct {
+				path, alias string
+				want        bool
+			}{
+				{"authors/jo/_index.md", "/author/jo/", tt.archives},
+				{"tags/news/_index.md", "/tag/news/", true},
+			} {
+				data, err := ioutil.ReadFile(filepath.Join(c.path, "content", page.path))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.Contains(string(data), page.alias); got != page.want {
+					t.Errorf("%s has alias %s = %v, want %v", page.path, page.alias, got, page.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package ghosttohugo
+
+import "path/filepath"
+
+// term returns the authors taxonomy term for the user. This is the Ghost
+// slug, so the term page keeps the URL it had in Ghost.
+func (u user) term() string {
+	if u.Slug != "" {
+		return u.Slug
+	}
+	return u.Name
+}
+
+func (u user) frontMatter() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"title": u.Name,
+	}
+
+	for key, value := range map[string]string{
+		"slug":        u.Slug,
+		"bio":         u.Bio,
+		"location":    u.Location,
+		"website":     u.Website,
+		"twitter":     u.Twitter,
+		"facebook":    u.Facebook,
+		"image":       u.ProfileImage,
+		"cover_image": u.CoverImage,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	if _, ok := metadata["image"]; !ok && u.Image != "" {
+		metadata["image"] = u.Image
+	}
+	if _, ok := metadata["cover_image"]; !ok && u.Cover != "" {
+		metadata["cover_image"] = u.Cover
+	}
+
+	return metadata
+}
+
+// writeAuthors writes a term page for each Ghost user to the authors
+// taxonomy, carrying their profile and an alias for their Ghost archive URL.
+func (c *Converter) writeAuthors() error {
+	for _, u := range c.info.Data.Users {
+		if u.Slug == "" {
+			continue
+		}
+
+		metadata := u.frontMatter()
+		if c.archives && !c.hasTaxonomyRoute("author") {
+			metadata["aliases"] = []string{"/author/" + u.Slug + "/"}
+		}
+
+		path := filepath.Join(c.path, "content", "authors", u.Slug, "_index.md")
+		if err := c.writeContent(path, u.Slug, metadata, u.Bio); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package ghosttohugo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_user_term(t *testing.T) {
+	tests := []struct {
+		name string
+		u    user
+		want string
+	}{
+		{"slug", user{Name: "Jane Doe", Slug: "jane"}, "jane"},
+		{"name", user{Name: "Jane Doe"}, "Jane Doe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.u.term(); got != tt.want {
+				t.Errorf("user.term() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_user_frontMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		u    user
+		want map[string]interface{}
+	}{
+		{"name_only", user{Name: "Jane"}, map[string]interface{}{"title": "Jane"}},
+		{
+			"profile",
+			user{
+				Name:         "Jane",
+				Slug:         "jane-doe",
+				Bio:          "Writer",
+				Location:     "Chicago",
+				Website:      "https://jane.example.com",
+				Twitter:      "@jane",
+				Facebook:     "jane",
+				ProfileImage: "/content/images/jane.jpg",
+				CoverImage:   "/content/images/cover.jpg",
+				Image:        "/content/images/old.jpg",
+			},
+			map[string]interface{}{
+				"title":       "Jane",
+				"slug":        "jane-doe",
+				"bio":         "Writer",
+				"location":    "Chicago",
+				"website":     "https://jane.example.com",
+				"twitter":     "@jane",
+				"facebook":    "jane",
+				"image":       "/content/images/jane.jpg",
+				"cover_image": "/content/images/cover.jpg",
+			},
+		},
+		{
+			"legacy_images",
+			user{Name: "Jane", Image: "/content/images/a.jpg", Cover: "/content/images/b.jpg"},
+			map[string]interface{}{
+				"title":       "Jane",
+				"image":       "/content/images/a.jpg",
+				"cover_image": "/content/images/b.jpg",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.u.frontMatter(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("user.frontMatter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
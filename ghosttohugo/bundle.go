@@ -0,0 +1,62 @@
+package ghosttohugo
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// bundleImages copies the images referenced by a post into its page bundle
+// at dir, and rewrites the references in content and in the front matter to
+// the resulting page resources. Images that can not be found in
+// the export keep their original reference.
+func (c *Converter) bundleImages(
+	dir string,
+	metadata map[string]interface{},
+	content string,
+) string {
+	if c.assets == nil {
+		return content
+	}
+
+	resources := make(map[string]string)
+	taken := make(map[string]bool)
+	resource := func(ref string) string {
+		if name, ok := assetName(ref); !ok || !strings.HasPrefix(name, "content/images/") {
+			return ref
+		}
+		if res, ok := resources[ref]; ok {
+			return res
+		}
+
+		res := ref
+		name := resourceName(ref, taken)
+		if c.writeAsset(ref, filepath.Join(dir, filepath.FromSlash(name))) {
+			taken[name] = true
+			res = (&url.URL{Path: name}).String()
+		}
+		resources[ref] = res
+		return res
+	}
+
+	mapStrings(metadata, func(s string) string {
+		return replaceAssetRefs(s, resource)
+	})
+
+	return replaceAssetRefs(content, resource)
+}
+
+// resourceName picks the file name of an image inside a page bundle. This is
+// the name of the uploaded file, unless another image in the bundle already
+// uses it.
+func resourceName(ref string, taken map[string]bool) string {
+	name, _ := assetName(ref)
+	if base := path.Base(name); !taken[base] {
+		return base
+	}
+
+	return strings.Replace(
+		strings.TrimPrefix(name, "content/images/"), "/", "-", -1,
+	)
+}
@@ -0,0 +1,83 @@
+package ghosttohugo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_resourceName(t *testing.T) {
+	tests := []struct {
+		name  string
+		ref   string
+		taken map[string]bool
+		want  string
+	}{
+		{"free", "/images/2020/01/a.jpg", nil, "a.jpg"},
+		{"content", "/content/images/2020/01/a.jpg", nil, "a.jpg"},
+		{"escaped", "/images/2020/my%20pic.jpg", nil, "my pic.jpg"},
+		{
+			"taken",
+			"/images/size/w600/2020/a.jpg",
+			map[string]bool{"a.jpg": true},
+			"size-w600-2020-a.jpg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resourceName(tt.ref, tt.taken); got != tt.want {
+				t.Errorf("resourceName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_bundleImages(t *testing.T) {
+	export := t.TempDir()
+	for _, name := range []string{"a.jpg", "my pic.jpg"} {
+		p := filepath.Join(export, "content", "images", "2020", name)
+		if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &Converter{
+		path:   t.TempDir(),
+		assets: &assets{fs: dirFS(export), copied: make(map[string]bool)},
+	}
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(c.path, "content", "post", "test")
+	metadata := map[string]interface{}{"image": "/images/2020/a.jpg"}
+	content := "{{< figure src=\"/images/2020/a.jpg\" >}}\n" +
+		"![pic](/content/images/2020/my%20pic.jpg)\n" +
+		"![missing](/images/2020/missing.jpg)\n" +
+		"[file](/files/doc.pdf)\n"
+
+	got := c.bundleImages(dir, metadata, content)
+	want := "{{< figure src=\"a.jpg\" >}}\n" +
+		"![pic](my%20pic.jpg)\n" +
+		"![missing](/images/2020/missing.jpg)\n" +
+		"[file](/files/doc.pdf)\n"
+	if got != want {
+		t.Errorf("Converter.bundleImages() = %q, want %q", got, want)
+	}
+
+	wantMeta := map[string]interface{}{"image": "a.jpg"}
+	if !reflect.DeepEqual(metadata, wantMeta) {
+		t.Errorf("Converter.bundleImages() metadata = %v, want %v", metadata, wantMeta)
+	}
+
+	for _, name := range []string{"a.jpg", "my pic.jpg"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Converter.bundleImages() did not copy %s: %v", name, err)
+		}
+	}
+}
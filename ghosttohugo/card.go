@@ -3,6 +3,7 @@ package ghosttohugo
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	jww "github.com/spf13/jwalterweatherman"
 )
@@ -26,60 +27,33 @@ func cardBookmark(payload interface{}) string {
 		return ""
 	}
 
-	url, ok := metadata["url"]
+	url, ok := metadata["url"].(string)
 	if !ok {
 		jww.ERROR.Println("cardBookmark: missing url")
 		return ""
 	}
-	title, ok := metadata["title"]
+	title, ok := metadata["title"].(string)
 	if !ok {
 		jww.ERROR.Println("cardBookmark: missing title")
 		return ""
 	}
-	description, ok := metadata["description"]
-	if !ok || description == nil {
+	description, ok := metadata["description"].(string)
+	if !ok {
 		jww.ERROR.Println("cardBookmark: missing description")
 		return ""
 	}
 
-	var thumbnail, icon, author, publisher, caption string
-	thumb, ok := metadata["thumbnail"]
-	if ok && thumb != nil {
-		jww.TRACE.Println("cardBookmark: found thumbnail")
-		thumbnail = stripContentFolder(thumb.(string))
-	}
-	iconinfo, ok := metadata["icon"]
-	if ok && iconinfo != nil {
-		jww.TRACE.Println("cardBookmark: found icon")
-		icon = stripContentFolder(iconinfo.(string))
-	}
-	authorinfo, ok := metadata["author"]
-	if ok && authorinfo != nil {
-		jww.TRACE.Println("cardBookmark: found author")
-		author = authorinfo.(string)
-	}
-	publisherinfo, ok := metadata["publisher"]
-	if ok && publisherinfo != nil {
-		jww.TRACE.Println("cardBookmark: found publisher")
-		publisher = publisherinfo.(string)
-	}
-	capt, ok := m["caption"]
-	if ok && capt != nil {
-		jww.TRACE.Println("cardBookmark: found caption")
-		caption = capt.(string)
-	}
-
 	return fmt.Sprintf(
 		"{{< bookmark url=%q title=%q description=%q icon=%q"+
 			" author=%q publisher=%q thumbnail=%q caption=%q >}}",
-		url.(string),
-		title.(string),
-		description.(string),
-		icon,
-		author,
-		publisher,
-		thumbnail,
-		caption,
+		url,
+		title,
+		description,
+		stripContentFolder(cardText(metadata, "icon")),
+		cardText(metadata, "author"),
+		cardText(metadata, "publisher"),
+		stripContentFolder(cardText(metadata, "thumbnail")),
+		cardText(m, "caption"),
 	)
 }
 
@@ -90,7 +64,7 @@ func cardCode(payload interface{}) string {
 		return ""
 	}
 
-	code, ok := m["code"]
+	code, ok := m["code"].(string)
 	if !ok {
 		jww.ERROR.Println("cardCode: missing code")
 		return ""
@@ -99,38 +73,21 @@ func cardCode(payload interface{}) string {
 	var buf bytes.Buffer
 
 	buf.WriteString("```")
-	if lang, ok := m["language"]; ok {
-		buf.WriteString(lang.(string))
-	}
+	buf.WriteString(cardText(m, "language"))
 	buf.WriteString("\n")
-	buf.WriteString(code.(string))
+	buf.WriteString(code)
 	buf.WriteString("\n```\n")
 
 	return buf.String()
 }
 
-func cardEmbed(payload interface{}) string {
-	m, ok := payload.(map[string]interface{})
-	if !ok {
-		jww.ERROR.Println("cardEmbed: payload not correct type")
-		return ""
-	}
-
-	html, ok := m["html"]
-	if !ok {
-		jww.ERROR.Println("cardEmbed: missing html")
-		return ""
-	}
-
-	return html.(string)
-}
 func cardGallery(payload interface{}) string {
 	m, ok := payload.(map[string]interface{})
 	if !ok {
 		jww.ERROR.Println("cardGallery: payload not correct type")
 		return ""
 	}
-	images, ok := m["images"]
+	images, ok := m["images"].([]interface{})
 	if !ok {
 		jww.ERROR.Println("cardGallery: missing images")
 		return ""
@@ -138,26 +95,34 @@ func cardGallery(payload interface{}) string {
 
 	var buf bytes.Buffer
 	buf.WriteString("{{< gallery")
-	if caption, ok := m["caption"]; ok {
-		buf.WriteString(fmt.Sprintf(" caption=\"%s\"", caption.(string)))
-	}
+	cardParams(&buf, "caption", cardCaption(m))
 	buf.WriteString(" >}}\n")
 
-	for _, img := range images.([]interface{}) {
+	for _, img := range images {
 		image, ok := img.(map[string]interface{})
 		if !ok {
 			continue
 		}
-		buf.WriteString("{{< galleryImg ")
-		buf.WriteString(fmt.Sprintf(" src=%q", stripContentFolder(image["src"].(string))))
-		buf.WriteString(fmt.Sprintf(" width=\"%.0f\"", image["width"].(float64)))
-		buf.WriteString(fmt.Sprintf(" height=\"%.0f\"", image["height"].(float64)))
-		if alt, ok := image["alt"]; ok {
-			buf.WriteString(fmt.Sprintf(" alt=%q", alt.(string)))
+		src := cardText(image, "src")
+		if src == "" {
+			continue
+		}
+		var width, height string
+		if w, ok := image["width"].(float64); ok && w > 0 {
+			width = fmt.Sprintf("%.0f", w)
 		}
-		if title, ok := image["title"]; ok {
-			buf.WriteString(fmt.Sprintf(" title=%q", title.(string)))
+		if h, ok := image["height"].(float64); ok && h > 0 {
+			height = fmt.Sprintf("%.0f", h)
 		}
+		buf.WriteString("{{< galleryImg")
+		cardParams(&buf,
+			"src", stripContentFolder(src),
+			"width", width,
+			"height", height,
+			"alt", htmlPlainText(cardText(image, "alt")),
+			"title", htmlPlainText(cardText(image, "title")),
+			"link", cardText(image, "href"),
+		)
 		buf.WriteString(" >}}")
 	}
 
@@ -177,11 +142,7 @@ func cardHTML(payload interface{}) string {
 		return ""
 	}
 
-	if html, ok := m["html"]; ok {
-		return html.(string)
-	}
-
-	return ""
+	return cardText(m, "html")
 }
 
 func cardImage(payload interface{}) string {
@@ -191,24 +152,31 @@ func cardImage(payload interface{}) string {
 		return ""
 	}
 
-	src, ok := m["src"]
-	if !ok {
+	src := cardText(m, "src")
+	if src == "" {
 		jww.ERROR.Println("cardImage: missing src")
 		return ""
 	}
 
-	if caption, ok := m["caption"]; ok {
-		return fmt.Sprintf(
-			"{{< figure src=\"%s\" caption=\"%s\" >}}\n",
-			stripContentFolder(src.(string)),
-			caption,
-		)
+	var class string
+	switch width := cardText(m, "cardWidth"); width {
+	case "wide", "full":
+		class = "kg-width-" + width
 	}
 
-	return fmt.Sprintf(
-		"{{< figure src=\"%s\" >}}\n",
-		stripContentFolder(src.(string)),
+	var buf bytes.Buffer
+	buf.WriteString("{{< figure")
+	cardParams(&buf,
+		"src", stripContentFolder(src),
+		"alt", htmlPlainText(cardText(m, "alt")),
+		"title", htmlPlainText(cardText(m, "title")),
+		"link", cardText(m, "href"),
+		"caption", cardCaption(m),
+		"class", class,
 	)
+	buf.WriteString(" >}}\n")
+
+	return buf.String()
 }
 
 func cardMarkdown(payload interface{}) string {
@@ -217,8 +185,294 @@ func cardMarkdown(payload interface{}) string {
 		jww.ERROR.Println("cardMarkdown: payload not correct type")
 		return ""
 	}
-	if markdown, ok := m["markdown"]; ok {
-		return fmt.Sprintf("%s\n", markdown.(string))
+	if markdown, ok := m["markdown"].(string); ok {
+		return fmt.Sprintf("%s\n", markdown)
 	}
 	return ""
 }
+
+// cardText returns the string field key of a card payload, or an empty
+// string when it is missing.
+func cardText(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// cardInline converts a rich text field of a card, which Koenig stores as
+// HTML, into markdown that fits in a shortcode parameter.
+func cardInline(m map[string]interface{}, key string) string {
+	return strings.TrimSpace(htmlMarkdown(cardText(m, key)))
+}
+
+func cardCallout(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("cardCallout: payload not correct type")
+		return ""
+	}
+
+	color := cardText(m, "backgroundColor")
+	if color == "" {
+		color = "grey"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{{< callout")
+	cardParams(&buf, "emoji", cardText(m, "calloutEmoji"), "color", color)
+	fmt.Fprintf(&buf, " >}}\n%s\n{{< /callout >}}\n", cardInline(m, "calloutText"))
+
+	return buf.String()
+}
+
+func cardToggle(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("cardToggle: payload not correct type")
+		return ""
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{{< toggle")
+	cardParams(&buf, "heading", cardLine(m, "heading"))
+	fmt.Fprintf(&buf, " >}}\n%s\n{{< /toggle >}}\n", cardInline(m, "content"))
+
+	return buf.String()
+}
+
+func cardButton(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("cardButton: payload not correct type")
+		return ""
+	}
+
+	url := cardText(m, "buttonUrl")
+	if url == "" {
+		jww.ERROR.Println("cardButton: missing buttonUrl")
+		return ""
+	}
+	alignment := cardText(m, "alignment")
+	if alignment == "" {
+		alignment = "left"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{{< button")
+	cardParams(&buf,
+		"url", url,
+		"text", cardText(m, "buttonText"),
+		"alignment", alignment,
+	)
+	buf.WriteString(" >}}\n")
+
+	return buf.String()
+}
+
+func cardHeader(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("cardHeader: payload not correct type")
+		return ""
+	}
+
+	size := cardText(m, "size")
+	if size == "" {
+		size = "small"
+	}
+	style := cardText(m, "style")
+	if style == "" {
+		style = "dark"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{{< header")
+	cardParams(&buf,
+		"size", size,
+		"style", style,
+		"header", cardLine(m, "header"),
+		"subheader", cardLine(m, "subheader"),
+		"image", stripContentFolder(cardText(m, "backgroundImageSrc")),
+	)
+	if enabled, _ := m["buttonEnabled"].(bool); enabled {
+		cardParams(&buf,
+			"buttonText", cardText(m, "buttonText"),
+			"buttonUrl", cardText(m, "buttonUrl"),
+		)
+	}
+	buf.WriteString(" >}}\n")
+
+	return buf.String()
+}
+
+// cardDuration formats a media duration given in seconds as m:ss, or h:mm:ss
+// for an hour or longer.
+func cardDuration(m map[string]interface{}) string {
+	seconds, _ := m["duration"].(float64)
+	if seconds <= 0 {
+		return ""
+	}
+
+	s := int(seconds + 0.5)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// cardSize formats a file size given in bytes
+func cardSize(m map[string]interface{}) string {
+	size, _ := m["fileSize"].(float64)
+	switch {
+	case size <= 0:
+		return ""
+	case size < 1024:
+		return fmt.Sprintf("%.0f Bytes", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.0f KB", size/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", size/1024/1024)
+	}
+}
+
+// cardParams writes the shortcode parameters that have a value
+func cardParams(buf *bytes.Buffer, params ...string) {
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i+1] != "" {
+			fmt.Fprintf(buf, " %s=%s", params[i], shortcodeQuote(params[i+1]))
+		}
+	}
+}
+
+// shortcodeQuote quotes a shortcode parameter value. Hugo drops every
+// backslash of a quoted value with escaped quotes, which would undo markdown
+// escapes, so values with backslashes or quotes are written as raw strings.
+// Values that can not be raw strings as they have backticks keep them and
+// their quotes as entities, since Hugo rejects escaped backticks.
+func shortcodeQuote(value string) string {
+	switch {
+	case !strings.ContainsAny(value, `\"`):
+		return `"` + value + `"`
+	case !strings.Contains(value, "`"):
+		return "`" + value + "`"
+	case !strings.Contains(value, `\`):
+		return `"` + strings.Replace(value, `"`, `\"`, -1) + `"`
+	}
+	return `"` + strings.NewReplacer("\\`", "&#96;", `"`, "&#34;").Replace(value) + `"`
+}
+
+// cardLine converts a rich text field of a card like cardInline, on a single
+// line as quoted shortcode parameters can not span lines.
+func cardLine(m map[string]interface{}, key string) string {
+	return strings.Join(strings.Fields(cardInline(m, key)), " ")
+}
+
+// cardCaption returns the caption of a card, which Ghost keeps as HTML, as
+// markdown on a single line.
+func cardCaption(m map[string]interface{}) string {
+	return cardLine(m, "caption")
+}
+
+func cardAudio(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("cardAudio: payload not correct type")
+		return ""
+	}
+
+	src := cardText(m, "src")
+	if src == "" {
+		jww.ERROR.Println("cardAudio: missing src")
+		return ""
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{{< audio")
+	cardParams(&buf,
+		"src", stripContentFolder(src),
+		"title", cardText(m, "title"),
+		"duration", cardDuration(m),
+		"thumbnail", stripContentFolder(cardText(m, "thumbnailSrc")),
+	)
+	buf.WriteString(" >}}\n")
+
+	return buf.String()
+}
+
+func cardVideo(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("cardVideo: payload not correct type")
+		return ""
+	}
+
+	src := cardText(m, "src")
+	if src == "" {
+		jww.ERROR.Println("cardVideo: missing src")
+		return ""
+	}
+
+	thumbnail := cardText(m, "customThumbnailSrc")
+	if thumbnail == "" {
+		thumbnail = cardText(m, "thumbnailSrc")
+	}
+	var width, height, loop string
+	if w, ok := m["width"].(float64); ok && w > 0 {
+		width = fmt.Sprintf("%.0f", w)
+	}
+	if h, ok := m["height"].(float64); ok && h > 0 {
+		height = fmt.Sprintf("%.0f", h)
+	}
+	if l, _ := m["loop"].(bool); l {
+		loop = "true"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{{< video")
+	cardParams(&buf,
+		"src", stripContentFolder(src),
+		"thumbnail", stripContentFolder(thumbnail),
+		"duration", cardDuration(m),
+		"width", width,
+		"height", height,
+		"loop", loop,
+		"caption", cardCaption(m),
+	)
+	buf.WriteString(" >}}\n")
+
+	return buf.String()
+}
+
+func cardFile(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("cardFile: payload not correct type")
+		return ""
+	}
+
+	src := cardText(m, "src")
+	if src == "" {
+		jww.ERROR.Println("cardFile: missing src")
+		return ""
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{{< file")
+	cardParams(&buf,
+		"src", stripContentFolder(src),
+		"title", cardText(m, "fileTitle"),
+		"caption", cardText(m, "fileCaption"),
+		"name", cardText(m, "fileName"),
+		"size", cardSize(m),
+	)
+	buf.WriteString(" >}}\n")
+
+	return buf.String()
+}
+
+// paywallMarker is how Ghost marks the end of the public preview of a post.
+// It is left in the content for applyPaywall.
+const paywallMarker = "<!--members-only-->"
+
+func cardPaywall(payload interface{}) string {
+	return paywallMarker + "\n"
+}
@@ -134,6 +134,42 @@ func Test_cardImage(t *testing.T) {
 			}},
 			"{{< figure src=\"test\" caption=\"caption\" >}}\n",
 		},
+		{
+			"full",
+			args{map[string]interface{}{
+				"src":       "/content/images/a.jpg",
+				"alt":       "A <b>cat</b>",
+				"title":     "Cat",
+				"href":      "https://example.com/",
+				"caption":   `Photo by <a href="https://example.com/">Jane</a>`,
+				"cardWidth": "wide",
+			}},
+			"{{< figure src=\"/images/a.jpg\" alt=\"A cat\" title=\"Cat\"" +
+				" link=\"https://example.com/\"" +
+				" caption=\"Photo by [Jane](https://example.com/)\"" +
+				" class=\"kg-width-wide\" >}}\n",
+		},
+		{
+			"regular_width",
+			args{map[string]interface{}{"src": "test", "cardWidth": ""}},
+			"{{< figure src=\"test\" >}}\n",
+		},
+		{
+			"caption_quotes",
+			args{map[string]interface{}{
+				"src":     "test",
+				"caption": `Say "cheese" <i>now</i>`,
+			}},
+			"{{< figure src=\"test\" caption=`Say \"cheese\" _now_` >}}\n",
+		},
+		{
+			"caption_escapes",
+			args{map[string]interface{}{
+				"src":     "test",
+				"caption": "2 * 3 <br>is six",
+			}},
+			"{{< figure src=\"test\" caption=`2 \\* 3 is six` >}}\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -169,3 +205,344 @@ func Test_cardMarkdown(t *testing.T) {
 		})
 	}
 }
+
+func Test_cardCallout(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{
+			"empty",
+			map[string]interface{}{},
+			"{{< callout color=\"grey\" >}}\n\n{{< /callout >}}\n",
+		},
+		{
+			"callout",
+			map[string]interface{}{
+				"calloutEmoji":    "💡",
+				"calloutText":     "Read <b>this</b>",
+				"backgroundColor": "blue",
+			},
+			"{{< callout emoji=\"💡\" color=\"blue\" >}}\nRead **this**\n{{< /callout >}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardCallout(tt.payload); got != tt.want {
+				t.Errorf("cardCallout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cardToggle(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{
+			"toggle",
+			map[string]interface{}{
+				"heading": "<span>Why \"this\"?</span>",
+				"content": "<p>One</p><p>Two</p>",
+			},
+			"{{< toggle heading=`Why \"this\"?` >}}\nOne\n\nTwo\n{{< /toggle >}}\n",
+		},
+		{
+			"markdown_escapes",
+			map[string]interface{}{
+				"heading": "Set <code>x</code> and my_var",
+				"content": "<p>Done</p>",
+			},
+			"{{< toggle heading=\"Set `x` and my\\_var\" >}}\nDone\n{{< /toggle >}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardToggle(tt.payload); got != tt.want {
+				t.Errorf("cardToggle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cardButton(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{"missing_url", map[string]interface{}{"buttonText": "Go"}, ""},
+		{
+			"default_alignment",
+			map[string]interface{}{"buttonText": "Go", "buttonUrl": "/a/"},
+			"{{< button url=\"/a/\" text=\"Go\" alignment=\"left\" >}}\n",
+		},
+		{
+			"center",
+			map[string]interface{}{
+				"buttonText": "Go",
+				"buttonUrl":  "/a/",
+				"alignment":  "center",
+			},
+			"{{< button url=\"/a/\" text=\"Go\" alignment=\"center\" >}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardButton(tt.payload); got != tt.want {
+				t.Errorf("cardButton() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cardHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{
+			"defaults",
+			map[string]interface{}{"header": "Hello"},
+			"{{< header size=\"small\" style=\"dark\" header=\"Hello\" >}}\n",
+		},
+		{
+			"full",
+			map[string]interface{}{
+				"size":               "large",
+				"style":              "image",
+				"header":             "Big <i>title</i>",
+				"subheader":          "More",
+				"backgroundImageSrc": "/content/images/a.jpg",
+				"buttonEnabled":      true,
+				"buttonText":         "Join",
+				"buttonUrl":          "#join",
+			},
+			"{{< header size=\"large\" style=\"image\" header=\"Big _title_\"" +
+				" subheader=\"More\" image=\"/images/a.jpg\"" +
+				" buttonText=\"Join\" buttonUrl=\"#join\" >}}\n",
+		},
+		{
+			"line_break",
+			map[string]interface{}{
+				"header":    "First<br>second",
+				"subheader": "my_var",
+			},
+			"{{< header size=\"small\" style=\"dark\" header=\"First second\"" +
+				" subheader=`my\\_var` >}}\n",
+		},
+		{
+			"button_disabled",
+			map[string]interface{}{
+				"header":        "Hello",
+				"buttonEnabled": false,
+				"buttonText":    "Join",
+			},
+			"{{< header size=\"small\" style=\"dark\" header=\"Hello\" >}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardHeader(tt.payload); got != tt.want {
+				t.Errorf("cardHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cardAudio(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{"no_src", map[string]interface{}{"title": "Episode"}, ""},
+		{
+			"full",
+			map[string]interface{}{
+				"src":          "/content/media/2021/ep1.mp3",
+				"title":        "Episode 1",
+				"duration":     3725.4,
+				"thumbnailSrc": "/content/media/2021/ep1_thumb.jpg",
+			},
+			"{{< audio src=\"/media/2021/ep1.mp3\"" +
+				" title=\"Episode 1\" duration=\"1:02:05\"" +
+				" thumbnail=\"/media/2021/ep1_thumb.jpg\" >}}\n",
+		},
+		{
+			"minimal",
+			map[string]interface{}{"src": "/content/media/a.mp3", "duration": 65.0},
+			"{{< audio src=\"/media/a.mp3\" duration=\"1:05\" >}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardAudio(tt.payload); got != tt.want {
+				t.Errorf("cardAudio() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cardVideo(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{"no_src", map[string]interface{}{"caption": "Clip"}, ""},
+		{
+			"full",
+			map[string]interface{}{
+				"src":                "/content/media/v.mp4",
+				"thumbnailSrc":       "/content/media/v_thumb.jpg",
+				"customThumbnailSrc": "/content/images/poster.jpg",
+				"duration":           9.0,
+				"width":              1280.0,
+				"height":             720.0,
+				"loop":               true,
+				"caption":            "A <b>clip</b>",
+			},
+			"{{< video src=\"/media/v.mp4\" thumbnail=\"/images/poster.jpg\"" +
+				" duration=\"0:09\" width=\"1280\" height=\"720\" loop=\"true\"" +
+				" caption=\"A **clip**\" >}}\n",
+		},
+		{
+			"caption_break",
+			map[string]interface{}{
+				"src":     "/content/media/v.mp4",
+				"caption": "line one<br>line two",
+			},
+			"{{< video src=\"/media/v.mp4\" caption=\"line one line two\" >}}\n",
+		},
+		{
+			"thumbnail",
+			map[string]interface{}{
+				"src":          "/content/media/v.mp4",
+				"thumbnailSrc": "/content/media/v_thumb.jpg",
+				"loop":         false,
+			},
+			"{{< video src=\"/media/v.mp4\" thumbnail=\"/media/v_thumb.jpg\" >}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardVideo(tt.payload); got != tt.want {
+				t.Errorf("cardVideo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cardFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{"no_src", map[string]interface{}{"fileName": "a.pdf"}, ""},
+		{
+			"full",
+			map[string]interface{}{
+				"src":         "/content/files/2021/report.pdf",
+				"fileTitle":   "Annual report",
+				"fileCaption": "Figures for 2021",
+				"fileName":    "report.pdf",
+				"fileSize":    2621440.0,
+			},
+			"{{< file src=\"/files/2021/report.pdf\" title=\"Annual report\"" +
+				" caption=\"Figures for 2021\" name=\"report.pdf\"" +
+				" size=\"2.5 MB\" >}}\n",
+		},
+		{
+			"small",
+			map[string]interface{}{
+				"src":      "/content/files/a.txt",
+				"fileName": "a.txt",
+				"fileSize": 2048.0,
+			},
+			"{{< file src=\"/files/a.txt\" name=\"a.txt\" size=\"2 KB\" >}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardFile(tt.payload); got != tt.want {
+				t.Errorf("cardFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cardGallery(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    string
+	}{
+		{"non_map", nil, ""},
+		{"no_images", map[string]interface{}{}, ""},
+		{
+			"images",
+			map[string]interface{}{
+				"images": []interface{}{
+					map[string]interface{}{
+						"src":    "/content/images/a.jpg",
+						"width":  800.0,
+						"height": 600.0,
+						"alt":    "A",
+						"title":  `The "A"`,
+						"href":   "/a/",
+					},
+					map[string]interface{}{"src": "/content/images/b.jpg"},
+					map[string]interface{}{"alt": "no src"},
+				},
+				"caption": "<b>Two</b> images",
+			},
+			"{{< gallery caption=\"**Two** images\" >}}\n" +
+				"{{< galleryImg src=\"/images/a.jpg\" width=\"800\" height=\"600\"" +
+				" alt=\"A\" title=`The \"A\"` link=\"/a/\" >}}" +
+				"{{< galleryImg src=\"/images/b.jpg\" >}}" +
+				"{{< /gallery >}}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cardGallery(tt.payload); got != tt.want {
+				t.Errorf("cardGallery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_shortcodeQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "a caption", `"a caption"`},
+		{"quotes", `a "caption"`, "`a \"caption\"`"},
+		{"backslash", `a \_caption\_`, "`a \\_caption\\_`"},
+		{"backtick", "a `code` \"caption\"", "\"a `code` \\\"caption\\\"\""},
+		{"escaped_backtick", "a \\`b\\` c\\_d", "\"a &#96;b&#96; c\\_d\""},
+		{"escaped_backtick_quotes", "say \"hi\" \\`x\\` a\\\\b", "\"say &#34;hi&#34; &#96;x&#96; a\\\\b\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortcodeQuote(tt.value); got != tt.want {
+				t.Errorf("shortcodeQuote() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
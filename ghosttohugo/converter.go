@@ -3,8 +3,10 @@ package ghosttohugo
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,12 +23,37 @@ type Converter struct {
 	dateformat string
 	path       string
 	force      bool
+	bundle     bool
+	baseURL    string
+	siteURLs   []string
+	offsite    []string // off-site references, as <slug> <url>
+	fetcher    *fetcher
+	categories string
+	catValues  []string
+	internal   string
+	rules      []internalRule
+	identifier string
+	head       bool
+	permalink  string
+	amp        bool
+	archives   bool
+	paywall    string
+	embeds     map[string]string
+	redirects  string
+	routesFile string
+	routes     *routes
+	pages      []pageURL
 	info       info
 	site       *hugolib.Site
 	kind       metadecoders.Format
+	assets     *assets
+
+	redirectAliases map[string][]string // by slug of the target page
 }
 
-// WithLocation sets the location used when working with timestamps
+// WithLocation sets the location used when working with timestamps. By
+// default this is the time zone of the Ghost site, or the local time zone
+// when the export does not have one.
 func WithLocation(location *time.Location) func(*Converter) {
 	return func(c *Converter) {
 		c.location = location
@@ -55,23 +82,254 @@ func WithForce() func(*Converter) {
 	}
 }
 
+// WithBundles sets the converter to write posts as Hugo page bundles, with
+// the images they reference copied next to them.
+func WithBundles() func(*Converter) {
+	return func(c *Converter) {
+		c.bundle = true
+	}
+}
+
+// WithBaseURL sets the URL the Hugo site will be served from
+func WithBaseURL(url string) func(*Converter) {
+	return func(c *Converter) {
+		c.baseURL = url
+	}
+}
+
+// WithSiteURLs sets the URLs the Ghost site was served from. Links to these
+// are rewritten to site relative paths.
+func WithSiteURLs(urls ...string) func(*Converter) {
+	return func(c *Converter) {
+		c.siteURLs = append(c.siteURLs, urls...)
+	}
+}
+
+// WithFetchImages sets the converter to download the remote images used by
+// posts, with at most limit downloads running at the same time.
+func WithFetchImages(limit int) func(*Converter) {
+	return func(c *Converter) {
+		c.fetcher = newFetcher(limit)
+	}
+}
+
+// Strategies for picking the categories of a post out of its tags
+const (
+	// CategoriesPrimary uses the primary tag of a post as its category
+	CategoriesPrimary = "primary"
+	// CategoriesPrefix uses the tags starting with one of the given prefixes
+	CategoriesPrefix = "prefix"
+	// CategoriesList uses the tags found in the given list
+	CategoriesList = "list"
+	// CategoriesNone does not assign categories
+	CategoriesNone = "none"
+)
+
+// WithCategories sets the strategy used to pick the categories of a post out
+// of its tags. The values are the prefixes or tag names used by the
+// CategoriesPrefix and CategoriesList strategies.
+func WithCategories(strategy string, values ...string) func(*Converter) {
+	return func(c *Converter) {
+		c.categories = strategy
+		c.catValues = values
+	}
+}
+
+// WithIdentifierKey sets the front matter key the Ghost uuid of a post is
+// written to, such as disqus_identifier, so comment threads keyed on it
+// carry over.
+func WithIdentifierKey(key string) func(*Converter) {
+	return func(c *Converter) {
+		c.identifier = key
+	}
+}
+
+// WithHeadPartial sets the converter to write a ghost_head.html partial that
+// adds the canonical URL and the comment identifier of a page to its head.
+func WithHeadPartial() func(*Converter) {
+	return func(c *Converter) {
+		c.head = true
+	}
+}
+
+// WithPermalink sets the permalink pattern the Ghost site served posts from,
+// such as /:year/:month/:slug/, instead of the one found in the export.
+func WithPermalink(pattern string) func(*Converter) {
+	return func(c *Converter) {
+		c.permalink = pattern
+	}
+}
+
+// WithAMPAliases sets the converter to add aliases for the AMP versions of
+// the Ghost posts.
+func WithAMPAliases() func(*Converter) {
+	return func(c *Converter) {
+		c.amp = true
+	}
+}
+
+// WithArchiveAliases sets the converter to add aliases for the Ghost author
+// archive URLs to the author term pages. Tag term pages always get one.
+func WithArchiveAliases() func(*Converter) {
+	return func(c *Converter) {
+		c.archives = true
+	}
+}
+
+// WithRedirects sets the Ghost redirects.json or redirects.yaml file to
+// convert along with the export.
+func WithRedirects(path string) func(*Converter) {
+	return func(c *Converter) {
+		c.redirects = path
+	}
+}
+
+// WithRoutes sets the Ghost routes.yaml file whose collections, channels and
+// taxonomies are reproduced in the Hugo site.
+func WithRoutes(path string) func(*Converter) {
+	return func(c *Converter) {
+		c.routesFile = path
+	}
+}
+
+// Policies for the content after the paywall card of members only posts
+const (
+	// PaywallTruncate leaves out the content after the paywall
+	PaywallTruncate = "truncate"
+	// PaywallMore replaces the paywall with a summary divider, so the public
+	// preview becomes the summary of the post
+	PaywallMore = "more"
+	// PaywallMembers wraps the content after the paywall in the members
+	// shortcode, for the theme to gate
+	PaywallMembers = "members"
+)
+
+// WithPaywall sets the policy for the content after the paywall card of
+// posts that are not public. By default it is left out (PaywallTruncate).
+func WithPaywall(policy string) func(*Converter) {
+	return func(c *Converter) {
+		c.paywall = policy
+	}
+}
+
+// WithEmbedShortcode sets the shortcode rendering the embeds of a provider
+// (youtube, vimeo, twitter, instagram or gist) instead of the built-in one.
+// EmbedHTML keeps the HTML of the provider.
+func WithEmbedShortcode(provider, shortcode string) func(*Converter) {
+	return func(c *Converter) {
+		if c.embeds == nil {
+			c.embeds = make(map[string]string)
+		}
+		c.embeds[provider] = shortcode
+	}
+}
+
+// Behaviors a post picks up from one of its internal tags
+const (
+	// InternalHide leaves the post out of page lists and feeds
+	InternalHide = "hide"
+	// InternalLayout renders the post with the layout given as value
+	InternalLayout = "layout"
+	// InternalFlag sets the front matter key given as value to true
+	InternalFlag = "flag"
+)
+
+// WithInternalTags sets the front matter key internal tags are listed under.
+// Dotted keys, such as params.internalTags, are written as nested tables.
+func WithInternalTags(key string) func(*Converter) {
+	return func(c *Converter) {
+		c.internal = key
+	}
+}
+
+// WithInternalTagRule adds a behavior to the posts carrying the internal
+// tag name, given with or without its leading #.
+func WithInternalTagRule(name, behavior, value string) func(*Converter) {
+	return func(c *Converter) {
+		c.rules = append(c.rules, internalRule{
+			tag:      strings.TrimPrefix(name, "#"),
+			behavior: behavior,
+			value:    value,
+		})
+	}
+}
+
 // New creates a new Converter configured with optional settings.
 func New(options ...func(*Converter)) (*Converter, error) {
 	c := &Converter{
 		dateformat: time.RFC3339,
-		location:   time.Local,
 		path:       "newhugosite",
 		kind:       metadecoders.TOML,
+		categories: CategoriesPrimary,
+		internal:   "internalTags",
+		identifier: "disqus_identifier",
+		paywall:    PaywallTruncate,
 	}
 
 	for _, option := range options {
 		option(c)
 	}
 
+	switch c.categories {
+	case CategoriesPrimary, CategoriesPrefix, CategoriesList, CategoriesNone:
+	default:
+		return nil, fmt.Errorf("unknown categories strategy %q", c.categories)
+	}
+
+	switch c.paywall {
+	case PaywallTruncate, PaywallMore, PaywallMembers:
+	default:
+		return nil, fmt.Errorf("unknown paywall policy %q", c.paywall)
+	}
+
+	for provider, shortcode := range c.embeds {
+		if _, ok := embedShortcodes[provider]; !ok {
+			return nil, fmt.Errorf("unknown embed provider %q", provider)
+		}
+		if shortcode == "" {
+			return nil, fmt.Errorf("missing shortcode for %s embeds", provider)
+		}
+	}
+
+	for _, rule := range c.rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
+// resolveLocation picks the location used for timestamps when none was given,
+// from the active_timezone setting of the export.
+func (c *Converter) resolveLocation() {
+	if c.location != nil {
+		return
+	}
+
+	c.location = time.Local
+	for _, key := range []string{"active_timezone", "activeTimezone"} {
+		name := c.info.settings[key]
+		if name == "" {
+			continue
+		}
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			jww.WARN.Printf("ignoring %s setting %q (%v)\n", key, name, err)
+			continue
+		}
+		c.location = location
+		break
+	}
+
+	jww.FEEDBACK.Printf("Using time zone %s\n", c.location)
+}
+
 func (c Converter) parseTime(raw json.RawMessage) time.Time {
+	if len(raw) == 0 || string(raw) == "null" {
+		return time.Time{}
+	}
+
 	var pt int64
 	if err := json.Unmarshal(raw, &pt); err == nil {
 		return time.Unix(0, pt*int64(time.Millisecond)).In(c.location)
@@ -108,28 +366,84 @@ func (c Converter) parseTime(raw json.RawMessage) time.Time {
 func (c Converter) populatePost(p *post) {
 	p.Published = c.parseTime(p.PublishedAt)
 	p.Created = c.parseTime(p.CreatedAt)
+	p.Updated = c.parseTime(p.UpdatedAt)
+	p.Meta = c.info.meta[string(p.ID)]
 
-	for _, user := range c.info.Data.Users {
-		if bytes.Equal(user.ID, p.AuthorID) {
-			p.Author = user.Name
-			break
+	var postauthors []postauthor
+	for _, postauthor := range c.info.Data.PostAuthors {
+		if bytes.Equal(postauthor.PostID, p.ID) {
+			postauthors = append(postauthors, postauthor)
+		}
+	}
+	sort.SliceStable(postauthors, func(i, j int) bool {
+		return postauthors[i].SortOrder < postauthors[j].SortOrder
+	})
+
+	authorIDs := []json.RawMessage{p.AuthorID}
+	if len(postauthors) > 0 {
+		authorIDs = authorIDs[:0]
+		for _, postauthor := range postauthors {
+			authorIDs = append(authorIDs, postauthor.AuthorID)
 		}
 	}
 
+	for _, id := range authorIDs {
+		for _, user := range c.info.Data.Users {
+			if bytes.Equal(user.ID, id) {
+				if p.Author == "" {
+					p.Author = user.Name
+				}
+				p.Authors = append(p.Authors, user.term())
+				break
+			}
+		}
+	}
+
+	var posttags []posttag
 	for _, posttag := range c.info.Data.PostTags {
-		if !bytes.Equal(posttag.PostID, p.ID) {
-			continue
+		if bytes.Equal(posttag.PostID, p.ID) {
+			posttags = append(posttags, posttag)
 		}
+	}
+	sort.SliceStable(posttags, func(i, j int) bool {
+		return posttags[i].SortOrder < posttags[j].SortOrder
+	})
 
+	for _, posttag := range posttags {
 		for _, tag := range c.info.Data.Tags {
 			if bytes.Equal(tag.ID, posttag.TagID) {
-				p.Tags = append(p.Tags, strings.TrimPrefix(tag.Name, "#"))
+				if tag.isInternal() {
+					p.InternalTags = append(p.InternalTags, tag.internalName())
+					p.InternalSlugs = append(p.InternalSlugs, tag.Slug)
+					break
+				}
+				if c.isCategory(tag, len(p.Tags) == 0) {
+					p.Categories = append(p.Categories, tag.term())
+				}
+				p.Tags = append(p.Tags, tag.term())
 				break
 			}
 		}
 	}
 }
 
+// ConvertPath converts the Ghost export found at path. This can be the JSON
+// export itself, or a zip archive or directory that holds the JSON along with
+// the content/images, content/media and content/files folders. Uploaded files
+// referenced by the posts are copied into the static folder of the Hugo site.
+func (c *Converter) ConvertPath(path string) (int, error) {
+	r, a, err := openExport(path)
+	if err != nil {
+		return 0, err
+	}
+	if a != nil {
+		defer a.Close()
+		c.assets = a
+	}
+
+	return c.Convert(r)
+}
+
 // Convert is the main function of this package. It takes an io.ReadSeeker
 // to the Ghost Blog export and converts that into a new Hugo site.
 func (c *Converter) Convert(r io.ReadSeeker) (int, error) {
@@ -141,10 +455,37 @@ func (c *Converter) Convert(r io.ReadSeeker) (int, error) {
 		return 0, err
 	}
 
+	c.resolveLocation()
+
+	if c.routesFile != "" {
+		routes, err := readRoutes(c.routesFile)
+		if err != nil {
+			return 0, fmt.Errorf("unable to read routes: %v", err)
+		}
+		c.routes = routes
+	}
+	c.indexPosts()
+
 	if err := c.createSite(); err != nil {
 		return 0, err
 	}
 
+	if err := c.writeAuthors(); err != nil {
+		return 0, err
+	}
+
+	if err := c.writeTags(); err != nil {
+		return 0, err
+	}
+
+	if err := c.writeChannels(); err != nil {
+		return 0, err
+	}
+
+	if err := c.writeRedirects(); err != nil {
+		return 0, err
+	}
+
 	decoder := json.NewDecoder(r)
 	err := seekTo(decoder, "posts")
 	if err != nil {
@@ -169,5 +510,16 @@ func (c *Converter) Convert(r io.ReadSeeker) (int, error) {
 		count++
 	}
 
+	if len(c.offsite) > 0 {
+		if err := c.writeOffsite(); err != nil {
+			return count, err
+		}
+	}
+	if c.fetcher != nil && len(c.fetcher.failures) > 0 {
+		if err := c.writeFailures(); err != nil {
+			return count, err
+		}
+	}
+
 	return count, nil
 }
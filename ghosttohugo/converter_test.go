@@ -0,0 +1,284 @@
+package ghosttohugo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConverter_populatePost_authors(t *testing.T) {
+	c := Converter{
+		location: time.UTC,
+		info: info{Data: data{
+			Users: []user{
+				{ID: json.RawMessage(`"1"`), Name: "first"},
+				{ID: json.RawMessage(`"2"`), Name: "second"},
+				{ID: json.RawMessage(`"3"`), Name: "Third", Slug: "third"},
+			},
+			PostAuthors: []postauthor{
+				{PostID: json.RawMessage(`"10"`), AuthorID: json.RawMessage(`"3"`), SortOrder: 1},
+				{PostID: json.RawMessage(`"10"`), AuthorID: json.RawMessage(`"2"`), SortOrder: 0},
+				{PostID: json.RawMessage(`"11"`), AuthorID: json.RawMessage(`"1"`), SortOrder: 0},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		p           post
+		wantAuthor  string
+		wantAuthors []string
+	}{
+		{"none", post{ID: json.RawMessage(`"12"`)}, "", nil},
+		{
+			"legacy",
+			post{ID: json.RawMessage(`"12"`), AuthorID: json.RawMessage(`"1"`)},
+			"first",
+			[]string{"first"},
+		},
+		{
+			"ordered",
+			post{ID: json.RawMessage(`"10"`), AuthorID: json.RawMessage(`"1"`)},
+			"second",
+			[]string{"second", "third"},
+		},
+		{
+			"primary_slug",
+			post{ID: json.RawMessage(`"13"`), AuthorID: json.RawMessage(`"3"`)},
+			"Third",
+			[]string{"third"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.p
+			c.populatePost(&p)
+			if p.Author != tt.wantAuthor {
+				t.Errorf("Converter.populatePost() author = %v, want %v", p.Author, tt.wantAuthor)
+			}
+			if !reflect.DeepEqual(p.Authors, tt.wantAuthors) {
+				t.Errorf("Converter.populatePost() authors = %v, want %v", p.Authors, tt.wantAuthors)
+			}
+		})
+	}
+}
+
+func TestConverter_populatePost_tags(t *testing.T) {
+	c := Converter{
+		location: time.UTC,
+		info: info{Data: data{
+			Tags: []tag{
+				{ID: json.RawMessage(`"1"`), Name: "Getting Started", Slug: "getting-started"},
+				{ID: json.RawMessage(`"2"`), Name: "News"},
+			},
+			PostTags: []posttag{
+				{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"2"`), SortOrder: 1},
+				{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"1"`), SortOrder: 0},
+				{PostID: json.RawMessage(`"11"`), TagID: json.RawMessage(`"2"`)},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name string
+		p    post
+		want []string
+	}{
+		{"none", post{ID: json.RawMessage(`"12"`)}, nil},
+		{"single", post{ID: json.RawMessage(`"11"`)}, []string{"News"}},
+		{"slugs", post{ID: json.RawMessage(`"10"`)}, []string{"getting-started", "News"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.p
+			c.populatePost(&p)
+			if !reflect.DeepEqual(p.Tags, tt.want) {
+				t.Errorf("Converter.populatePost() tags = %v, want %v", p.Tags, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_populatePost_categories(t *testing.T) {
+	tags := []tag{
+		{ID: json.RawMessage(`"1"`), Name: "Travel", Slug: "travel"},
+		{ID: json.RawMessage(`"2"`), Name: "cat: Food", Slug: "cat-food"},
+		{ID: json.RawMessage(`"3"`), Name: "News", Slug: "news"},
+	}
+	posttags := []posttag{
+		{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"3"`), SortOrder: 2},
+		{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"1"`), SortOrder: 0},
+		{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"2"`), SortOrder: 1},
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		values   []string
+		want     []string
+	}{
+		{"unset", "", nil, nil},
+		{"none", CategoriesNone, nil, nil},
+		{"primary", CategoriesPrimary, nil, []string{"travel"}},
+		{"prefix", CategoriesPrefix, []string{"cat:"}, []string{"cat-food"}},
+		{"prefix_slug", CategoriesPrefix, []string{"new"}, []string{"news"}},
+		{"list", CategoriesList, []string{"news", "TRAVEL"}, []string{"travel", "news"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{
+				location:   time.UTC,
+				categories: tt.strategy,
+				catValues:  tt.values,
+				info:       info{Data: data{Tags: tags, PostTags: posttags}},
+			}
+			p := post{ID: json.RawMessage(`"10"`)}
+			c.populatePost(&p)
+			if !reflect.DeepEqual(p.Categories, tt.want) {
+				t.Errorf("Converter.populatePost() categories = %v, want %v", p.Categories, tt.want)
+			}
+			if want := []string{"travel", "cat-food", "news"}; !reflect.DeepEqual(p.Tags, want) {
+				t.Errorf("Converter.populatePost() tags = %v, want %v", p.Tags, want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []func(*Converter)
+		wantErr bool
+	}{
+		{"defaults", nil, false},
+		{"categories", []func(*Converter){WithCategories(CategoriesNone)}, false},
+		{"bad_categories", []func(*Converter){WithCategories("bad")}, true},
+		{
+			"internal_rule",
+			[]func(*Converter){WithInternalTagRule("#hide", InternalHide, "")},
+			false,
+		},
+		{
+			"internal_rule_value",
+			[]func(*Converter){WithInternalTagRule("#page", InternalLayout, "")},
+			true,
+		},
+		{
+			"bad_internal_rule",
+			[]func(*Converter){WithInternalTagRule("#hide", "bad", "")},
+			true,
+		},
+		{"paywall", []func(*Converter){WithPaywall(PaywallMembers)}, false},
+		{"bad_paywall", []func(*Converter){WithPaywall("bad")}, true},
+		{"embed", []func(*Converter){WithEmbedShortcode("twitter", EmbedHTML)}, false},
+		{"bad_embed", []func(*Converter){WithEmbedShortcode("codepen", "pen")}, true},
+		{"empty_embed", []func(*Converter){WithEmbedShortcode("vimeo", "")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.options...); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConverter_populatePost_internalTags(t *testing.T) {
+	c := Converter{
+		location:   time.UTC,
+		categories: CategoriesPrimary,
+		info: info{Data: data{
+			Tags: []tag{
+				{ID: json.RawMessage(`"1"`), Name: "#hide-from-feed", Slug: "hash-hide-from-feed"},
+				{ID: json.RawMessage(`"2"`), Name: "News", Slug: "news"},
+				{ID: json.RawMessage(`"3"`), Name: "Hidden", Slug: "hidden", Visibility: "internal"},
+			},
+			PostTags: []posttag{
+				{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"1"`), SortOrder: 0},
+				{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"2"`), SortOrder: 1},
+				{PostID: json.RawMessage(`"10"`), TagID: json.RawMessage(`"3"`), SortOrder: 2},
+			},
+		}},
+	}
+
+	p := post{ID: json.RawMessage(`"10"`)}
+	c.populatePost(&p)
+	if want := []string{"news"}; !reflect.DeepEqual(p.Tags, want) {
+		t.Errorf("Converter.populatePost() tags = %v, want %v", p.Tags, want)
+	}
+	if want := []string{"news"}; !reflect.DeepEqual(p.Categories, want) {
+		t.Errorf("Converter.populatePost() categories = %v, want %v", p.Categories, want)
+	}
+	if want := []string{"hide-from-feed", "Hidden"}; !reflect.DeepEqual(p.InternalTags, want) {
+		t.Errorf("Converter.populatePost() internal tags = %v, want %v", p.InternalTags, want)
+	}
+	if want := []string{"hash-hide-from-feed", "hidden"}; !reflect.DeepEqual(p.InternalSlugs, want) {
+		t.Errorf("Converter.populatePost() internal slugs = %v, want %v", p.InternalSlugs, want)
+	}
+}
+
+func TestConverter_resolveLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		location *time.Location
+		settings map[string]string
+		want     string
+	}{
+		{"default", nil, map[string]string{}, "Local"},
+		{
+			"given",
+			time.UTC,
+			map[string]string{"active_timezone": "America/New_York"},
+			"UTC",
+		},
+		{
+			"active_timezone",
+			nil,
+			map[string]string{"active_timezone": "America/New_York"},
+			"America/New_York",
+		},
+		{
+			"legacy",
+			nil,
+			map[string]string{"activeTimezone": "Europe/London"},
+			"Europe/London",
+		},
+		{
+			"invalid",
+			nil,
+			map[string]string{"active_timezone": "Nowhere/Special"},
+			"Local",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{location: tt.location, info: info{settings: tt.settings}}
+			c.resolveLocation()
+			if got := c.location.String(); got != tt.want {
+				t.Errorf("Converter.resolveLocation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_parseTime(t *testing.T) {
+	c := Converter{location: time.UTC, dateformat: time.RFC3339}
+	tests := []struct {
+		name string
+		raw  json.RawMessage
+		want time.Time
+	}{
+		{"empty", nil, time.Time{}},
+		{"null", json.RawMessage("null"), time.Time{}},
+		{"millis", json.RawMessage("1577836800000"), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"string", json.RawMessage(`"2020-01-02T03:04:05.000Z"`), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.parseTime(tt.raw); !got.Equal(tt.want) {
+				t.Errorf("Converter.parseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
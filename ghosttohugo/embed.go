@@ -0,0 +1,140 @@
+package ghosttohugo
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jbarone/mobiledoc"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// EmbedHTML keeps the HTML of the embeds of a provider instead of rendering
+// them with a shortcode
+const EmbedHTML = "html"
+
+// embedShortcodes maps the providers recognized in embed cards to the Hugo
+// built-in shortcode rendering them
+var embedShortcodes = map[string]string{
+	"youtube":   "youtube",
+	"vimeo":     "vimeo",
+	"twitter":   "twitter",
+	"instagram": "instagram",
+	"gist":      "gist",
+}
+
+var (
+	embedID = regexp.MustCompile(`^[\w-]+$`)
+	vimeoID = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// embedProvider returns the provider of an embedded URL, along with the
+// arguments its shortcode takes. typ is the type Ghost recorded for the
+// embed, which marks tweets.
+func embedProvider(raw, typ string) (string, []string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", nil, false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range []string{"www.", "m.", "mobile."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+
+	var (
+		provider string
+		args     []string
+	)
+	switch {
+	case host == "youtu.be" && len(segments) > 0:
+		provider, args = "youtube", segments[:1]
+	case host == "youtube.com" || host == "youtube-nocookie.com":
+		provider = "youtube"
+		if v := u.Query().Get("v"); v != "" {
+			args = []string{v}
+		} else if len(segments) == 2 {
+			switch segments[0] {
+			case "embed", "shorts", "live":
+				args = segments[1:]
+			}
+		}
+	case host == "vimeo.com" || host == "player.vimeo.com":
+		// unlisted videos carry a hash after the id
+		provider = "vimeo"
+		for _, segment := range segments {
+			if vimeoID.MatchString(segment) {
+				args = []string{segment}
+				break
+			}
+		}
+	case host == "twitter.com" || host == "x.com" || typ == "twitter":
+		provider = "twitter"
+		if len(segments) >= 3 && segments[1] == "status" {
+			args = segments[2:3]
+		}
+	case host == "instagram.com" && len(segments) >= 2:
+		switch segments[0] {
+		case "p", "reel", "tv":
+			provider, args = "instagram", segments[1:2]
+		}
+	case host == "gist.github.com" && len(segments) == 2:
+		provider, args = "gist", segments
+	}
+
+	if provider == "" || len(args) == 0 {
+		return "", nil, false
+	}
+	for _, arg := range args {
+		if !embedID.MatchString(arg) {
+			return "", nil, false
+		}
+	}
+	return provider, args, true
+}
+
+// embedCard returns the renderer of embed cards. Embeds of known providers
+// become their shortcode, taken from shortcodes before the built-in ones,
+// the others keep the HTML of the provider.
+func embedCard(shortcodes map[string]string) mobiledoc.Card {
+	return func(payload interface{}) string {
+		m, ok := payload.(map[string]interface{})
+		if !ok {
+			jww.ERROR.Println("cardEmbed: payload not correct type")
+			return ""
+		}
+
+		typ := cardText(m, "embedType")
+		if typ == "" {
+			typ = cardText(m, "type")
+		}
+		if provider, args, ok := embedProvider(cardText(m, "url"), typ); ok {
+			shortcode, ok := shortcodes[provider]
+			if !ok {
+				shortcode = embedShortcodes[provider]
+			}
+			if shortcode != EmbedHTML {
+				var buf bytes.Buffer
+				buf.WriteString("{{< " + shortcode)
+				for _, arg := range args {
+					fmt.Fprintf(&buf, " %q", arg)
+				}
+				buf.WriteString(" >}}\n")
+				return buf.String()
+			}
+		}
+
+		html, ok := m["html"].(string)
+		if !ok {
+			jww.ERROR.Println("cardEmbed: missing html")
+			return ""
+		}
+		return html
+	}
+}
+
+func cardEmbed(payload interface{}) string {
+	return embedCard(nil)(payload)
+}
@@ -0,0 +1,95 @@
+package ghosttohugo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_embedProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		typ      string
+		provider string
+		args     []string
+		ok       bool
+	}{
+		{"youtube", "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=1", "video", "youtube", []string{"dQw4w9WgXcQ"}, true},
+		{"youtube_short", "https://youtu.be/dQw4w9WgXcQ", "video", "youtube", []string{"dQw4w9WgXcQ"}, true},
+		{"youtube_embed", "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", "", "youtube", []string{"dQw4w9WgXcQ"}, true},
+		{"youtube_channel", "https://www.youtube.com/c/ghost", "rich", "", nil, false},
+		{"vimeo", "https://vimeo.com/channels/staffpicks/146022717", "video", "vimeo", []string{"146022717"}, true},
+		{"vimeo_unlisted", "https://vimeo.com/146022717/2a6d4c4f5e", "video", "vimeo", []string{"146022717"}, true},
+		{"vimeo_showcase", "https://vimeo.com/showcase/ghost", "rich", "", nil, false},
+		{"vimeo_player", "https://player.vimeo.com/video/146022717", "video", "vimeo", []string{"146022717"}, true},
+		{"tweet", "https://twitter.com/Ghost/status/1390611475405197313", "twitter", "twitter", []string{"1390611475405197313"}, true},
+		{"x", "https://x.com/Ghost/status/1390611475405197313?s=20", "rich", "twitter", []string{"1390611475405197313"}, true},
+		{"twitter_profile", "https://twitter.com/Ghost", "rich", "", nil, false},
+		{"instagram", "https://www.instagram.com/p/BWNjjyYFxVx/", "rich", "instagram", []string{"BWNjjyYFxVx"}, true},
+		{"gist", "https://gist.github.com/spf13/7896402", "rich", "gist", []string{"spf13", "7896402"}, true},
+		{"unknown", "https://codepen.io/ghost/pen/abc", "rich", "", nil, false},
+		{"bad_id", "https://youtu.be/a%20b", "video", "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, args, ok := embedProvider(tt.url, tt.typ)
+			if provider != tt.provider || !reflect.DeepEqual(args, tt.args) || ok != tt.ok {
+				t.Errorf("embedProvider() = %q, %v, %v, want %q, %v, %v",
+					provider, args, ok, tt.provider, tt.args, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_embedCard(t *testing.T) {
+	youtube := map[string]interface{}{
+		"url":  "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"type": "video",
+		"html": "<iframe></iframe>",
+	}
+	tests := []struct {
+		name       string
+		shortcodes map[string]string
+		payload    interface{}
+		want       string
+	}{
+		{"non_map", nil, nil, ""},
+		{"default", nil, youtube, "{{< youtube \"dQw4w9WgXcQ\" >}}\n"},
+		{
+			"tweet",
+			nil,
+			map[string]interface{}{
+				"url":       "https://twitter.com/Ghost/status/1390611475405197313",
+				"embedType": "twitter",
+				"html":      "<blockquote></blockquote>",
+			},
+			"{{< twitter \"1390611475405197313\" >}}\n",
+		},
+		{
+			"gist",
+			nil,
+			map[string]interface{}{"url": "https://gist.github.com/spf13/7896402"},
+			"{{< gist \"spf13\" \"7896402\" >}}\n",
+		},
+		{
+			"custom_shortcode",
+			map[string]string{"youtube": "lite-youtube"},
+			youtube,
+			"{{< lite-youtube \"dQw4w9WgXcQ\" >}}\n",
+		},
+		{"keep_html", map[string]string{"youtube": EmbedHTML}, youtube, "<iframe></iframe>"},
+		{
+			"unknown",
+			nil,
+			map[string]interface{}{"url": "https://codepen.io/ghost/pen/abc", "html": "<iframe></iframe>"},
+			"<iframe></iframe>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := embedCard(tt.shortcodes)(tt.payload); got != tt.want {
+				t.Errorf("embedCard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
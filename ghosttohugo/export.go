@@ -0,0 +1,271 @@
+package ghosttohugo
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+var (
+	// assetRef matches site relative references to files uploaded to Ghost,
+	// with or without the leading /content folder
+	assetRef = regexp.MustCompile(
+		`(?:^|[\s"'(=])((?:/content)?/(?:images|media|files)/[^\s"'()<>\[\]\\?#]+)`,
+	)
+	// contentRef matches the /content path of uploaded files anywhere, such
+	// as in absolute URLs of the Ghost site
+	contentRef = regexp.MustCompile(
+		`(/content/(?:images|media|files)/[^\s"'()<>\[\]\\?#]+)`,
+	)
+)
+
+type assetFS interface {
+	Open(name string) (io.ReadCloser, error)
+	Close() error
+}
+
+type dirFS string
+
+func (d dirFS) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+func (d dirFS) Close() error {
+	return nil
+}
+
+type zipFS struct {
+	*zip.ReadCloser
+	files map[string]*zip.File
+}
+
+func (z zipFS) Open(name string) (io.ReadCloser, error) {
+	f, ok := z.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return f.Open()
+}
+
+// assets gives access to the files shipped alongside a Ghost export, such
+// as the uploads in content/images, content/media and content/files.
+type assets struct {
+	fs     assetFS
+	root   string
+	copied map[string]bool
+}
+
+func (a *assets) open(name string) (io.ReadCloser, error) {
+	return a.fs.Open(path.Join(a.root, name))
+}
+
+// Close releases the underlying archive
+func (a *assets) Close() error {
+	return a.fs.Close()
+}
+
+// openExport opens the Ghost export found at p. This can either be the JSON
+// export itself, or a zip archive or directory that holds the JSON along
+// with the content folder. The returned assets are nil when there is no
+// content folder to copy from.
+func openExport(p string) (io.ReadSeeker, *assets, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		fs    assetFS
+		names []string
+	)
+	switch {
+	case fi.IsDir():
+		fs = dirFS(p)
+		err = filepath.Walk(p, func(name string, fi os.FileInfo, err error) error {
+			if err != nil || fi.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(p, name)
+			names = append(names, filepath.ToSlash(rel))
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	case strings.EqualFold(filepath.Ext(p), ".zip"):
+		z, err := zip.OpenReader(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		zfs := zipFS{ReadCloser: z, files: make(map[string]*zip.File)}
+		for _, f := range z.File {
+			zfs.files[f.Name] = f
+			names = append(names, f.Name)
+		}
+		fs = zfs
+	default:
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bytes.NewReader(b), nil, nil
+	}
+
+	export, root, err := findExport(names)
+	if err != nil {
+		fs.Close()
+		return nil, nil, err
+	}
+
+	r, err := fs.Open(export)
+	if err != nil {
+		fs.Close()
+		return nil, nil, err
+	}
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		fs.Close()
+		return nil, nil, err
+	}
+
+	jww.INFO.Printf("using export %s\n", export)
+
+	a := &assets{fs: fs, root: root, copied: make(map[string]bool)}
+	return bytes.NewReader(b), a, nil
+}
+
+// findExport picks the JSON export out of a list of file names, and the
+// folder that holds the content tree.
+func findExport(names []string) (string, string, error) {
+	sort.Strings(names)
+
+	var export, root string
+	found := false
+	for _, name := range names {
+		if i := strings.Index(name, "content/"); i >= 0 && !found &&
+			assetRef.MatchString("/"+name[i:]) {
+			root = name[:i]
+			found = true
+		}
+
+		if !strings.HasSuffix(strings.ToLower(name), ".json") ||
+			strings.Contains(name, "content/") {
+			continue
+		}
+		switch path.Base(name) {
+		case "redirects.json", "package.json":
+			continue
+		}
+		if export == "" || (strings.Contains(name, ".ghost.") &&
+			!strings.Contains(export, ".ghost.")) {
+			export = name
+		}
+	}
+
+	if export == "" {
+		return "", "", errors.New("unable to find a JSON export")
+	}
+	if !found {
+		root = path.Dir(export)
+	}
+
+	return export, root, nil
+}
+
+// copyAssets copies every uploaded file referenced in texts into the static
+// folder of the Hugo site, at the path stripContentFolder rewrites the
+// references to.
+func (c *Converter) copyAssets(texts ...string) {
+	if c.assets == nil {
+		return
+	}
+
+	for _, text := range texts {
+		for _, re := range []*regexp.Regexp{assetRef, contentRef} {
+			for _, match := range re.FindAllStringSubmatch(text, -1) {
+				c.copyAsset(match[1])
+			}
+		}
+	}
+}
+
+func (c *Converter) copyAsset(ref string) {
+	name, ok := assetName(ref)
+	if !ok {
+		jww.WARN.Printf("skipping %s outside of the uploaded files\n", ref)
+		return
+	}
+	if c.assets.copied[name] {
+		return
+	}
+	c.assets.copied[name] = true
+
+	dst := filepath.Join(
+		c.path,
+		"static",
+		filepath.FromSlash(strings.TrimPrefix(name, "content/")),
+	)
+	c.writeAsset(ref, dst)
+}
+
+// writeAsset copies the uploaded file ref points to into dst. It reports
+// whether the file was found in the export.
+func (c *Converter) writeAsset(ref, dst string) bool {
+	name, ok := assetName(ref)
+	if !ok {
+		jww.WARN.Printf("skipping %s outside of the uploaded files\n", ref)
+		return false
+	}
+	r, err := c.assets.open(name)
+	if err != nil {
+		jww.WARN.Printf("unable to find %s in export (%v)\n", ref, err)
+		return false
+	}
+	defer r.Close()
+
+	if err := helpers.WriteToDisk(dst, r, c.site.Fs.Source); err != nil {
+		jww.ERROR.Printf("error copying %s (%v)\n", ref, err)
+		return false
+	}
+	return true
+}
+
+// assetDirs are the folders of the export holding uploaded files
+var assetDirs = []string{"content/images/", "content/media/", "content/files/"}
+
+// assetName returns the name of the file ref points to inside the export. It
+// reports false when the name leads out of the folders of uploaded files.
+func assetName(ref string) (string, bool) {
+	name := "content" + stripContentFolder(ref)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	name = path.Clean(name)
+
+	for _, dir := range assetDirs {
+		if strings.HasPrefix(name, dir) {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+// replaceAssetRefs replaces every reference to an uploaded file in text with
+// the result of repl.
+func replaceAssetRefs(text string, repl func(ref string) string) string {
+	return replaceSubmatch(assetRef, text, repl)
+}
@@ -0,0 +1,203 @@
+package ghosttohugo
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+)
+
+func Test_findExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		wantJSON string
+		wantRoot string
+		wantErr  bool
+	}{
+		{"empty", nil, "", "", true},
+		{"no_json", []string{"content/images/a.jpg"}, "", "", true},
+		{"json_only", []string{"export.json"}, "export.json", ".", false},
+		{
+			"content",
+			[]string{
+				"site/content/images/a.jpg",
+				"site/data/export.json",
+			},
+			"site/data/export.json",
+			"site/",
+			false,
+		},
+		{
+			"prefer_ghost",
+			[]string{
+				"a.json",
+				"redirects.json",
+				"site.ghost.2020-01-01.json",
+				"content/data/other.json",
+			},
+			"site.ghost.2020-01-01.json",
+			".",
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json, root, err := findExport(tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("findExport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if json != tt.wantJSON {
+				t.Errorf("findExport() json = %v, want %v", json, tt.wantJSON)
+			}
+			if root != tt.wantRoot {
+				t.Errorf("findExport() root = %v, want %v", root, tt.wantRoot)
+			}
+		})
+	}
+}
+
+func Test_openExport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"export.json":                    `{"db":[]}`,
+		"content/images/2020/my pic.jpg": "image",
+	}
+
+	tree := filepath.Join(dir, "tree")
+	for name, data := range files {
+		p := filepath.Join(tree, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archive := filepath.Join(dir, "export.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create("backup/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(data))
+	}
+	zw.Close()
+	f.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		wantAssets bool
+		wantErr    bool
+	}{
+		{"missing", filepath.Join(dir, "missing"), false, true},
+		{"json", filepath.Join(tree, "export.json"), false, false},
+		{"directory", tree, true, false},
+		{"zip", archive, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, a, err := openExport(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openExport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			b, _ := ioutil.ReadAll(r)
+			if string(b) != files["export.json"] {
+				t.Errorf("openExport() json = %q, want %q", b, files["export.json"])
+			}
+			if (a != nil) != tt.wantAssets {
+				t.Fatalf("openExport() assets = %v, want %v", a != nil, tt.wantAssets)
+			}
+			if a == nil {
+				return
+			}
+			defer a.Close()
+
+			img, err := a.open("content/images/2020/my pic.jpg")
+			if err != nil {
+				t.Fatalf("assets.open() error = %v", err)
+			}
+			img.Close()
+		})
+	}
+}
+
+func Test_assetName(t *testing.T) {
+	tests := []struct {
+		name   string
+		ref    string
+		want   string
+		wantOk bool
+	}{
+		{"image", "/content/images/a.jpg", "content/images/a.jpg", true},
+		{"stripped", "/media/a.mp3", "content/media/a.mp3", true},
+		{"escaped", "/content/files/a%20b.pdf", "content/files/a b.pdf", true},
+		{"cleaned", "/content/images/x/../a.jpg", "content/images/a.jpg", true},
+		{"traversal", "/content/images/../../../../x", "../../x", false},
+		{"escaped_traversal", "/content/images/%2e%2e/settings", "content/settings", false},
+		{"other_folder", "/content/images/../data/a.json", "content/data/a.json", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := assetName(tt.ref)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("assetName() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestConverter_copyAssets(t *testing.T) {
+	src := t.TempDir()
+	for _, name := range []string{"a.jpg", "b.jpg", "c.jpg"} {
+		path := filepath.Join(src, "content", "images", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "secret.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Converter{
+		path:   t.TempDir(),
+		kind:   metadecoders.TOML,
+		assets: &assets{fs: dirFS(src), copied: make(map[string]bool)},
+	}
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+	c.copyAssets(
+		"![a](/images/a.jpg) ![b](https://old.example.com/content/images/b.jpg)",
+		"https://cdn.example.com/images/c.jpg",
+		"![x](/content/images/../../secret.txt)",
+	)
+
+	for name, want := range map[string]bool{"a.jpg": true, "b.jpg": true, "c.jpg": false} {
+		_, err := os.Stat(filepath.Join(c.path, "static", "images", name))
+		if got := err == nil; got != want {
+			t.Errorf("static/images/%s copied = %v, want %v", name, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(c.path, "secret.txt")); err == nil {
+		t.Errorf("Converter.copyAssets() wrote outside of the static folder")
+	}
+}
@@ -0,0 +1,304 @@
+package ghosttohugo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/helpers"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// remoteImage matches the remote images referenced by markdown images, and
+// the HTML img tags and shortcodes that may reference remote images
+var remoteImage = regexp.MustCompile(
+	`!\[[^\]]*\]\((https?://[^\s"'()<>]+)|<img\b[^>]*>|` +
+		`\{\{< (figure|galleryImg|bookmark|audio|video) [^\n]*?>\}\}`,
+)
+
+// imageParams matches the parameters holding images in the img tags, which
+// have no name in remoteImage, and in the shortcodes matched by remoteImage
+var imageParams = map[string]*regexp.Regexp{
+	"":           imageParam("src"),
+	"figure":     imageParam("src"),
+	"galleryImg": imageParam("src"),
+	"bookmark":   imageParam("icon|thumbnail"),
+	"audio":      imageParam("thumbnail"),
+	"video":      imageParam("thumbnail"),
+}
+
+func imageParam(names string) *regexp.Regexp {
+	return regexp.MustCompile(`\b(?:` + names + `)="(https?://[^\s"'()<>]+)`)
+}
+
+// mapRemoteImages replaces every remote image referenced in content with the
+// result of fn.
+func mapRemoteImages(content string, fn func(string) string) string {
+	return remoteImage.ReplaceAllStringFunc(content, func(markup string) string {
+		match := remoteImage.FindStringSubmatch(markup)
+		if u := match[1]; u != "" {
+			return markup[:len(markup)-len(u)] + fn(u)
+		}
+		return replaceSubmatch(imageParams[match[2]], markup, fn)
+	})
+}
+
+// fetcher downloads remote images
+type fetcher struct {
+	client *http.Client
+	limit  int
+
+	mu       sync.Mutex
+	files    map[string]string // url to a downloaded copy on disk
+	failures map[string]error
+}
+
+func newFetcher(limit int) *fetcher {
+	if limit < 1 {
+		limit = 1
+	}
+	return &fetcher{
+		client:   &http.Client{Timeout: time.Minute},
+		limit:    limit,
+		files:    make(map[string]string),
+		failures: make(map[string]error),
+	}
+}
+
+// remoteImages collects the remote image URLs referenced in content and by
+// the image fields of the front matter.
+func remoteImages(metadata map[string]interface{}, content string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(u string) {
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+
+	mapRemoteImages(content, func(u string) string {
+		add(u)
+		return u
+	})
+	for key, value := range metadata {
+		if !strings.Contains(strings.ToLower(key), "image") {
+			continue
+		}
+		mapStrings(value, func(s string) string {
+			if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+				add(s)
+			}
+			return s
+		})
+	}
+
+	return urls
+}
+
+// fetchImages downloads the remote images referenced by a post into dir,
+// and rewrites the references in content and the front matter to point at
+// the local copies, using prefix as the location of dir. Images that fail to
+// download keep pointing at the remote URL.
+func (c *Converter) fetchImages(
+	dir, prefix string,
+	metadata map[string]interface{},
+	content string,
+) string {
+	urls := remoteImages(metadata, content)
+	if len(urls) == 0 {
+		return content
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		refs = make(map[string]string)
+		sem  = make(chan struct{}, c.fetcher.limit)
+	)
+	for _, u := range urls {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			name, err := c.fetchImage(u, dir)
+			if err != nil {
+				c.fetcher.fail(u, err)
+				return
+			}
+
+			mu.Lock()
+			refs[u] = prefix + name
+			mu.Unlock()
+		}(u)
+	}
+	wg.Wait()
+
+	local := func(u string) string {
+		if ref, ok := refs[u]; ok {
+			return ref
+		}
+		return u
+	}
+	for key, value := range metadata {
+		if strings.Contains(strings.ToLower(key), "image") {
+			metadata[key] = mapStrings(value, local)
+		}
+	}
+
+	return mapRemoteImages(content, local)
+}
+
+// fetchImage stores the image at u in dir, named after the hash of its
+// content, and returns the file name.
+func (c *Converter) fetchImage(u, dir string) (string, error) {
+	f := c.fetcher
+
+	f.mu.Lock()
+	cached, ok := f.files[u]
+	f.mu.Unlock()
+
+	var (
+		data []byte
+		ext  string
+		err  error
+	)
+	switch ok {
+	case true:
+		data, err = ioutil.ReadFile(cached)
+		ext = filepath.Ext(cached)
+	case false:
+		data, ext, err = f.download(u)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:8]) + ext
+	dst := filepath.Join(dir, name)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if exists, _ := helpers.Exists(dst, c.site.Fs.Source); !exists {
+		err = helpers.WriteToDisk(dst, bytes.NewReader(data), c.site.Fs.Source)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !ok {
+		f.files[u] = dst
+	}
+
+	return name, nil
+}
+
+func (f *fetcher) download(u string) ([]byte, string, error) {
+	jww.DEBUG.Printf("downloading %s\n", u)
+	resp, err := f.client.Get(u)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	ext := imageExt(resp.Header.Get("Content-Type"), u)
+	if ext == "" {
+		return nil, "", fmt.Errorf(
+			"unexpected content type %q",
+			resp.Header.Get("Content-Type"),
+		)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	return data, ext, err
+}
+
+func (f *fetcher) fail(u string, err error) {
+	jww.WARN.Printf("unable to download %s (%v)\n", u, err)
+
+	f.mu.Lock()
+	f.failures[u] = err
+	f.mu.Unlock()
+}
+
+// writeFailures writes the remote images that could not be downloaded to
+// failed-images.txt, one per line followed by the error.
+func (c *Converter) writeFailures() error {
+	urls := make([]string, 0, len(c.fetcher.failures))
+	for u := range c.fetcher.failures {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+
+	var buf bytes.Buffer
+	for _, u := range urls {
+		fmt.Fprintf(&buf, "%s %v\n", u, c.fetcher.failures[u])
+	}
+
+	path := filepath.Join(c.path, "failed-images.txt")
+	if err := helpers.WriteToDisk(path, &buf, c.site.Fs.Source); err != nil {
+		return err
+	}
+
+	jww.WARN.Printf(
+		"%d remote image(s) could not be downloaded, listed in %s\n",
+		len(urls), path,
+	)
+	return nil
+}
+
+// imageExt picks the file extension for a downloaded image, based on the
+// content type of the response, or the URL when that is not conclusive.
+// It returns an empty string for anything that is not an image.
+func imageExt(contentType, u string) string {
+	switch strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	case "image/avif":
+		return ".avif"
+	case "", "application/octet-stream", "binary/octet-stream":
+	default:
+		if !strings.HasPrefix(contentType, "image/") {
+			return ""
+		}
+	}
+
+	p := u
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	switch ext := strings.ToLower(path.Ext(p)); ext {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg", ".avif", ".bmp",
+		".tif", ".tiff", ".ico":
+		return ext
+	}
+
+	if strings.HasPrefix(contentType, "image/") {
+		return ".img"
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package ghosttohugo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_imageExt(t *testing.T) {
+	type args struct {
+		contentType, u string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"jpeg", args{"image/jpeg", "https://example.com/photo"}, ".jpg"},
+		{"params", args{"image/png; charset=binary", "https://example.com/a"}, ".png"},
+		{"octet_stream", args{"application/octet-stream", "https://example.com/a.GIF?w=1"}, ".gif"},
+		{"unknown_image", args{"image/x-icon", "https://example.com/icon"}, ".img"},
+		{"html", args{"text/html", "https://example.com/a.jpg"}, ""},
+		{"no_type", args{"", "https://example.com/a"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageExt(tt.args.contentType, tt.args.u); got != tt.want {
+				t.Errorf("imageExt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_fetchImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/a.png", "/copy-of-a":
+				w.Header().Set("Content-Type", "image/png")
+				w.Write([]byte("png"))
+			case "/page":
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte("<html></html>"))
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	))
+	defer server.Close()
+
+	c := &Converter{path: t.TempDir(), fetcher: newFetcher(2)}
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(c.path, "static", "images", "remote")
+	metadata := map[string]interface{}{
+		"image": server.URL + "/a.png",
+		"title": server.URL + "/a.png",
+	}
+	content := "![a](" + server.URL + "/a.png)\n" +
+		"{{< figure src=\"" + server.URL + "/copy-of-a\" >}}\n" +
+		"<img src=\"" + server.URL + "/page\">\n" +
+		"![missing](" + server.URL + "/missing.png)\n" +
+		"[link](" + server.URL + "/a.png)\n" +
+		"<iframe src=\"" + server.URL + "/page\"></iframe>\n" +
+		"{{< video src=\"" + server.URL + "/v.mp4\" thumbnail=\"" + server.URL + "/a.png\" >}}\n" +
+		"{{< bookmark url=\"" + server.URL + "/page\" icon=\"" + server.URL + "/a.png\"" +
+		" thumbnail=\"" + server.URL + "/copy-of-a\" >}}\n"
+
+	// sha256("png")
+	const name = "/images/remote/8f8cbb7dcf46e0bc.png"
+	want := "![a](" + name + ")\n" +
+		"{{< figure src=\"" + name + "\" >}}\n" +
+		"<img src=\"" + server.URL + "/page\">\n" +
+		"![missing](" + server.URL + "/missing.png)\n" +
+		"[link](" + server.URL + "/a.png)\n" +
+		"<iframe src=\"" + server.URL + "/page\"></iframe>\n" +
+		"{{< video src=\"" + server.URL + "/v.mp4\" thumbnail=\"" + name + "\" >}}\n" +
+		"{{< bookmark url=\"" + server.URL + "/page\" icon=\"" + name + "\"" +
+		" thumbnail=\"" + name + "\" >}}\n"
+
+	got := c.fetchImages(dir, "/images/remote/", metadata, content)
+	if got != want {
+		t.Errorf("Converter.fetchImages() = %q, want %q", got, want)
+	}
+
+	wantMeta := map[string]interface{}{
+		"image": name,
+		"title": server.URL + "/a.png",
+	}
+	if !reflect.DeepEqual(metadata, wantMeta) {
+		t.Errorf("Converter.fetchImages() metadata = %v, want %v", metadata, wantMeta)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(files) != 1 {
+		t.Errorf("Converter.fetchImages() wrote %v (%v), want a single file", files, err)
+	}
+	if len(c.fetcher.failures) != 2 {
+		t.Errorf("Converter.fetchImages() failures = %v, want 2", c.fetcher.failures)
+	}
+
+	if err := c.writeFailures(); err != nil {
+		t.Fatal(err)
+	}
+	report, err := ioutil.ReadFile(filepath.Join(c.path, "failed-images.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReport := server.URL + "/missing.png unexpected status 404 Not Found\n" +
+		server.URL + "/page unexpected content type \"text/html\"\n"
+	if string(report) != wantReport {
+		t.Errorf("Converter.writeFailures() = %q, want %q", report, wantReport)
+	}
+
+	bundle := filepath.Join(c.path, "content", "post", "test")
+	got = c.fetchImages(bundle, "", metadata, "![a]("+server.URL+"/a.png)")
+	if got != "![a](8f8cbb7dcf46e0bc.png)" {
+		t.Errorf("Converter.fetchImages() = %q for a bundle", got)
+	}
+	if _, err := os.Stat(filepath.Join(bundle, "8f8cbb7dcf46e0bc.png")); err != nil {
+		t.Errorf("Converter.fetchImages() did not copy into the bundle: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	jww "github.com/spf13/jwalterweatherman"
@@ -13,6 +14,87 @@ func stripContentFolder(original string) string {
 	return strings.TrimPrefix(original, "/content")
 }
 
+// wrapSpace wraps text in the given markers, keeping any surrounding
+// whitespace outside of them so markdown emphasis is still recognised.
+func wrapSpace(text, open, end string) string {
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return text
+	}
+	start := strings.Index(text, core)
+
+	return text[:start] + open + core + end + text[start+len(core):]
+}
+
+// replaceSubmatch replaces the first submatch of every match of re in text
+// with the result of repl.
+func replaceSubmatch(
+	re *regexp.Regexp,
+	text string,
+	repl func(string) string,
+) string {
+	var (
+		buf  strings.Builder
+		last int
+	)
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		buf.WriteString(text[last:loc[2]])
+		buf.WriteString(repl(text[loc[2]:loc[3]]))
+		last = loc[3]
+	}
+	buf.WriteString(text[last:])
+
+	return buf.String()
+}
+
+// mapStrings replaces every string found in v, which may be nested in
+// slices and maps, with the result of fn. Slices and maps are updated in
+// place.
+func mapStrings(v interface{}, fn func(string) string) interface{} {
+	switch v := v.(type) {
+	case string:
+		return fn(v)
+	case []string:
+		for i := range v {
+			v[i] = fn(v[i])
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = mapStrings(v[i], fn)
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = mapStrings(v[key], fn)
+		}
+	}
+	return v
+}
+
+// frontMatterStrings returns every string found in the front matter
+func frontMatterStrings(metadata map[string]interface{}) []string {
+	var strs []string
+	mapStrings(metadata, func(s string) string {
+		strs = append(strs, s)
+		return s
+	})
+	return strs
+}
+
+// setKey sets the value at a dotted key such as params.internalTags,
+// creating the maps along the way.
+func setKey(metadata map[string]interface{}, key string, value interface{}) {
+	keys := strings.Split(key, ".")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := metadata[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			metadata[k] = next
+		}
+		metadata = next
+	}
+	metadata[keys[len(keys)-1]] = value
+}
+
 func parseBool(rm json.RawMessage) bool {
 	var b bool
 	if err := json.Unmarshal(rm, &b); err == nil {
@@ -2,6 +2,7 @@ package ghosttohugo
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -26,3 +27,48 @@ func Test_parseBool(t *testing.T) {
 		})
 	}
 }
+
+func Test_wrapSpace(t *testing.T) {
+	type args struct {
+		text, open, end string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"empty", args{"", "**", "**"}, ""},
+		{"spaces", args{"  ", "**", "**"}, "  "},
+		{"plain", args{"text", "<u>", "</u>"}, "<u>text</u>"},
+		{"surrounding", args{" text\n", "_", "_"}, " _text_\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapSpace(tt.args.text, tt.args.open, tt.args.end); got != tt.want {
+				t.Errorf("wrapSpace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setKey(t *testing.T) {
+	metadata := map[string]interface{}{
+		"title":  "a",
+		"params": "not a table",
+	}
+	setKey(metadata, "draft", true)
+	setKey(metadata, "params.a.b", 1)
+	setKey(metadata, "params.c", 2)
+
+	want := map[string]interface{}{
+		"title": "a",
+		"draft": true,
+		"params": map[string]interface{}{
+			"a": map[string]interface{}{"b": 1},
+			"c": 2,
+		},
+	}
+	if !reflect.DeepEqual(metadata, want) {
+		t.Errorf("setKey() = %v, want %v", metadata, want)
+	}
+}
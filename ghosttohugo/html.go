@@ -0,0 +1,613 @@
+package ghosttohugo
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	jww "github.com/spf13/jwalterweatherman"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	htmlSpace   = regexp.MustCompile(`\s+`)
+	htmlEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"`", "\\`",
+		`*`, `\*`,
+		`_`, `\_`,
+		`[`, `\[`,
+		`]`, `\]`,
+		`<`, `\<`,
+	)
+	// htmlBlockStart matches what markdown reads as the start of a heading,
+	// quote or list when it begins a line of a paragraph
+	htmlBlockStart = regexp.MustCompile(`(?m)^[ \t]*(?:[#>+-]|[0-9]+[.)])`)
+)
+
+// htmlMarkdown converts a rendered Ghost HTML document into markdown.
+// Anything that has no markdown equivalent is passed through as raw HTML.
+func htmlMarkdown(src string) string {
+	nodes, err := html.ParseFragment(
+		strings.NewReader(src),
+		&html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body},
+	)
+	if err != nil {
+		jww.ERROR.Printf("error parsing html (%v)\n", err)
+		return src
+	}
+
+	md := htmlBlocks(nodes)
+	if md == "" {
+		return ""
+	}
+
+	return md + "\n"
+}
+
+// htmlPlainText returns the text of an HTML fragment on a single line,
+// without its markup.
+func htmlPlainText(src string) string {
+	if !strings.ContainsAny(src, "<&") {
+		return strings.Join(strings.Fields(src), " ")
+	}
+
+	nodes, err := html.ParseFragment(
+		strings.NewReader(src),
+		&html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body},
+	)
+	if err != nil {
+		jww.ERROR.Printf("error parsing html (%v)\n", err)
+		return src
+	}
+
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		buf.WriteString(htmlText(n))
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+func htmlChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, c)
+	}
+	return children
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func htmlHasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(htmlAttr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func htmlText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var buf bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		buf.WriteString(htmlText(c))
+	}
+	return buf.String()
+}
+
+func htmlFind(n *html.Node, a atom.Atom) []*html.Node {
+	var found []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == a {
+			found = append(found, c)
+			continue
+		}
+		found = append(found, htmlFind(c, a)...)
+	}
+	return found
+}
+
+func htmlRaw(n *html.Node) string {
+	var buf bytes.Buffer
+	if err := html.Render(&buf, n); err != nil {
+		jww.ERROR.Printf("error rendering html (%v)\n", err)
+		return ""
+	}
+	return buf.String()
+}
+
+func htmlIsBlock(n *html.Node) bool {
+	if n.Type == html.CommentNode {
+		return true
+	}
+	if n.Type != html.ElementNode {
+		return false
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Blockquote, atom.Pre, atom.Hr, atom.Figure,
+		atom.Table, atom.Div, atom.Section, atom.Article, atom.Header,
+		atom.Footer, atom.Main, atom.Aside, atom.Nav, atom.Iframe,
+		atom.Script, atom.Style, atom.Video, atom.Audio, atom.Details,
+		atom.Form, atom.Dl:
+		return true
+	}
+	return false
+}
+
+// htmlBlocks renders a list of sibling nodes as markdown blocks separated by
+// blank lines. Runs of inline nodes are grouped into paragraphs.
+func htmlBlocks(nodes []*html.Node) string {
+	var (
+		blocks []string
+		inline []*html.Node
+	)
+
+	flush := func() {
+		var buf bytes.Buffer
+		for _, n := range inline {
+			buf.WriteString(htmlInline(n))
+		}
+		if text := strings.TrimSpace(buf.String()); text != "" {
+			blocks = append(blocks, htmlEscapeLines(text))
+		}
+		inline = nil
+	}
+
+	for _, n := range nodes {
+		if !htmlIsBlock(n) {
+			inline = append(inline, n)
+			continue
+		}
+		flush()
+		if block := strings.Trim(htmlBlock(n), "\n"); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	flush()
+
+	return strings.Join(blocks, "\n\n")
+}
+
+func htmlBlock(n *html.Node) string {
+	if n.Type == html.CommentNode {
+		if strings.HasPrefix(strings.TrimSpace(n.Data), "kg-card-") {
+			return ""
+		}
+		return htmlRaw(n)
+	}
+
+	switch n.DataAtom {
+	case atom.P:
+		return htmlEscapeLines(strings.TrimSpace(htmlInlineChildren(n)))
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return strings.Repeat("#", level) + " " +
+			strings.TrimSpace(htmlInlineChildren(n))
+	case atom.Ul, atom.Ol:
+		return htmlList(n)
+	case atom.Blockquote:
+		return htmlQuote(n)
+	case atom.Pre:
+		return htmlPre(n)
+	case atom.Hr:
+		return cardHR(nil)
+	case atom.Figure:
+		return htmlFigure(n)
+	case atom.Table:
+		return htmlTable(n)
+	case atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.Main:
+		// Ghost wraps rendered cards in containers such as
+		// <div class="kg-card kg-callout-card">, which carry meaning
+		// markdown can not express.
+		if htmlHasClass(n, "kg-card") {
+			return htmlRaw(n)
+		}
+		return htmlBlocks(htmlChildren(n))
+	}
+
+	return htmlRaw(n)
+}
+
+// htmlEscapeLines escapes the characters that would turn the lines of a
+// paragraph into other blocks
+func htmlEscapeLines(text string) string {
+	return htmlBlockStart.ReplaceAllStringFunc(text, func(s string) string {
+		i := len(s) - 1
+		return s[:i] + `\` + s[i:]
+	})
+}
+
+func htmlInlineChildren(n *html.Node) string {
+	var buf bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		buf.WriteString(htmlInline(c))
+	}
+	return buf.String()
+}
+
+func htmlInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return htmlEscaper.Replace(htmlSpace.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Strong, atom.B:
+		return wrapSpace(htmlInlineChildren(n), "**", "**")
+	case atom.Em, atom.I:
+		return wrapSpace(htmlInlineChildren(n), "_", "_")
+	case atom.S, atom.Del, atom.Strike:
+		return wrapSpace(htmlInlineChildren(n), "~~", "~~")
+	case atom.Code, atom.Kbd, atom.Samp:
+		code := htmlText(n)
+		if strings.Contains(code, "`") {
+			return "`` " + code + " ``"
+		}
+		return "`" + code + "`"
+	case atom.A:
+		text := htmlInlineChildren(n)
+		href := htmlAttr(n, "href")
+		if href == "" {
+			return text
+		}
+		if title := htmlAttr(n, "title"); title != "" {
+			return fmt.Sprintf("[%s](%s %q)", text, href, title)
+		}
+		return fmt.Sprintf("[%s](%s)", text, href)
+	case atom.Img:
+		src := stripContentFolder(htmlAttr(n, "src"))
+		if title := htmlAttr(n, "title"); title != "" {
+			return fmt.Sprintf("![%s](%s %q)", htmlAttr(n, "alt"), src, title)
+		}
+		return fmt.Sprintf("![%s](%s)", htmlAttr(n, "alt"), src)
+	case atom.Br:
+		return "  \n"
+	case atom.Span, atom.Font:
+		return htmlInlineChildren(n)
+	}
+
+	if htmlIsBlock(n) {
+		return htmlInlineChildren(n)
+	}
+	if n.FirstChild == nil {
+		return htmlRaw(n)
+	}
+
+	// keep the element, but still convert what it contains
+	var buf bytes.Buffer
+	buf.WriteString("<" + n.Data)
+	for _, attr := range n.Attr {
+		fmt.Fprintf(&buf, " %s=\"%s\"", attr.Key, html.EscapeString(attr.Val))
+	}
+	buf.WriteString(">")
+	buf.WriteString(htmlInlineChildren(n))
+	buf.WriteString("</" + n.Data + ">")
+	return buf.String()
+}
+
+func htmlList(n *html.Node) string {
+	number := 1
+	if start, err := strconv.Atoi(htmlAttr(n, "start")); err == nil {
+		number = start
+	}
+
+	var items []string
+	for _, li := range htmlChildren(n) {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "* "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		content := htmlBlocks(htmlChildren(li))
+		if !htmlIsLoose(li) {
+			content = strings.Replace(content, "\n\n", "\n", -1)
+		}
+
+		lines := strings.Split(content, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = "    " + lines[i]
+			}
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// htmlIsLoose reports whether a list item holds block content that needs
+// to stay separated by blank lines.
+func htmlIsLoose(li *html.Node) bool {
+	for c := li.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.DataAtom {
+		case atom.Ul, atom.Ol:
+		default:
+			if htmlIsBlock(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func htmlQuote(n *html.Node) string {
+	lines := strings.Split(htmlBlocks(htmlChildren(n)), "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+			continue
+		}
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// htmlLanguage finds the code language from the language-* or lang-* class
+// highlighters add to <pre> and <code> elements.
+func htmlLanguage(nodes ...*html.Node) string {
+	for _, n := range nodes {
+		for _, class := range strings.Fields(htmlAttr(n, "class")) {
+			for _, prefix := range []string{"language-", "lang-"} {
+				if strings.HasPrefix(class, prefix) {
+					return strings.TrimPrefix(class, prefix)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func htmlPre(n *html.Node) string {
+	nodes := []*html.Node{n}
+	nodes = append(nodes, htmlFind(n, atom.Code)...)
+
+	payload := map[string]interface{}{
+		"code": strings.TrimSuffix(htmlText(n), "\n"),
+	}
+	if lang := htmlLanguage(nodes...); lang != "" {
+		payload["language"] = lang
+	}
+
+	return cardCode(payload)
+}
+
+func htmlFigure(n *html.Node) string {
+	// the caption is kept as HTML for the image and gallery cards, which
+	// convert it themselves
+	var caption, captionHTML string
+	for _, c := range htmlFind(n, atom.Figcaption) {
+		caption = strings.TrimSpace(htmlInlineChildren(c))
+		var buf bytes.Buffer
+		for _, child := range htmlChildren(c) {
+			buf.WriteString(htmlRaw(child))
+		}
+		captionHTML = buf.String()
+	}
+
+	if htmlHasClass(n, "kg-bookmark-card") {
+		return htmlBookmark(n, caption)
+	}
+
+	if pre := htmlFind(n, atom.Pre); len(pre) > 0 {
+		code := htmlPre(pre[0])
+		if caption != "" {
+			code += "\n" + caption
+		}
+		return code
+	}
+
+	// other cards, such as embeds and products, carry meaning markdown can
+	// not express
+	if htmlHasClass(n, "kg-card") && !htmlHasClass(n, "kg-image-card") &&
+		!htmlHasClass(n, "kg-gallery-card") {
+		return htmlRaw(n)
+	}
+
+	images := htmlFind(n, atom.Img)
+	switch len(images) {
+	case 0:
+		return htmlRaw(n)
+	case 1:
+		payload := htmlImagePayload(n, images[0])
+		for _, width := range []string{"wide", "full"} {
+			if htmlHasClass(n, "kg-width-"+width) {
+				payload["cardWidth"] = width
+			}
+		}
+		if captionHTML != "" {
+			payload["caption"] = captionHTML
+		}
+		return cardImage(payload)
+	}
+
+	var imgs []interface{}
+	for _, img := range images {
+		imgs = append(imgs, htmlImagePayload(n, img))
+	}
+	payload := map[string]interface{}{"images": imgs}
+	if captionHTML != "" {
+		payload["caption"] = captionHTML
+	}
+	return cardGallery(payload)
+}
+
+// htmlBookmark converts a rendered bookmark card back into the bookmark
+// card payload.
+func htmlBookmark(n *html.Node, caption string) string {
+	text := func(class string) string {
+		if c := htmlFindClass(n, class); c != nil {
+			return strings.TrimSpace(htmlText(c))
+		}
+		return ""
+	}
+	src := func(class string) string {
+		c := htmlFindClass(n, class)
+		if c == nil {
+			return ""
+		}
+		if c.DataAtom != atom.Img {
+			imgs := htmlFind(c, atom.Img)
+			if len(imgs) == 0 {
+				return ""
+			}
+			c = imgs[0]
+		}
+		return htmlAttr(c, "src")
+	}
+
+	var url string
+	if a := htmlFindClass(n, "kg-bookmark-container"); a != nil {
+		url = htmlAttr(a, "href")
+	}
+
+	return cardBookmark(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"url":         url,
+			"title":       text("kg-bookmark-title"),
+			"description": text("kg-bookmark-description"),
+			"icon":        src("kg-bookmark-icon"),
+			"author":      text("kg-bookmark-author"),
+			"publisher":   text("kg-bookmark-publisher"),
+			"thumbnail":   src("kg-bookmark-thumbnail"),
+		},
+		"caption": caption,
+	})
+}
+
+// htmlFindClass returns the first element below n with the class
+func htmlFindClass(n *html.Node, class string) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && htmlHasClass(c, class) {
+			return c
+		}
+		if found := htmlFindClass(c, class); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// htmlImagePayload returns the image card payload of an img element of the
+// figure, with the link wrapping it.
+func htmlImagePayload(figure, img *html.Node) map[string]interface{} {
+	payload := map[string]interface{}{
+		"src":   htmlAttr(img, "src"),
+		"alt":   htmlAttr(img, "alt"),
+		"title": htmlAttr(img, "title"),
+	}
+	if width, err := strconv.ParseFloat(htmlAttr(img, "width"), 64); err == nil {
+		payload["width"] = width
+	}
+	if height, err := strconv.ParseFloat(htmlAttr(img, "height"), 64); err == nil {
+		payload["height"] = height
+	}
+	for p := img.Parent; p != nil && p != figure; p = p.Parent {
+		if p.Type == html.ElementNode && p.DataAtom == atom.A {
+			payload["href"] = htmlAttr(p, "href")
+			break
+		}
+	}
+	return payload
+}
+
+func htmlTable(n *html.Node) string {
+	rows := htmlFind(n, atom.Tr)
+	if len(rows) == 0 || !htmlIsSimpleTable(rows) {
+		return htmlRaw(n)
+	}
+
+	var (
+		buf    bytes.Buffer
+		aligns []string
+	)
+	for i, row := range rows {
+		var cells []string
+		for _, cell := range htmlChildren(row) {
+			if cell.Type != html.ElementNode {
+				continue
+			}
+			text := strings.TrimSpace(htmlInlineChildren(cell))
+			text = strings.Replace(text, "\n", " ", -1)
+			cells = append(cells, strings.Replace(text, "|", `\|`, -1))
+
+			if i == 0 {
+				aligns = append(aligns, htmlAlign(cell))
+			}
+		}
+
+		buf.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			buf.WriteString("| " + strings.Join(aligns, " | ") + " |\n")
+		}
+	}
+
+	return buf.String()
+}
+
+// htmlIsSimpleTable reports whether every cell can be expressed as a
+// markdown table cell.
+func htmlIsSimpleTable(rows []*html.Node) bool {
+	for _, row := range rows {
+		for _, cell := range htmlChildren(row) {
+			if cell.Type != html.ElementNode {
+				continue
+			}
+			if htmlAttr(cell, "colspan") != "" || htmlAttr(cell, "rowspan") != "" {
+				return false
+			}
+			for c := cell.FirstChild; c != nil; c = c.NextSibling {
+				if htmlIsBlock(c) && c.DataAtom != atom.P {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func htmlAlign(cell *html.Node) string {
+	align := htmlAttr(cell, "align")
+	style := strings.Replace(htmlAttr(cell, "style"), " ", "", -1)
+	if i := strings.Index(style, "text-align:"); i >= 0 {
+		align = strings.TrimSuffix(style[i+len("text-align:"):], ";")
+		align = strings.SplitN(align, ";", 2)[0]
+	}
+
+	switch strings.ToLower(align) {
+	case "left":
+		return ":---"
+	case "right":
+		return "---:"
+	case "center":
+		return ":---:"
+	}
+	return "---"
+}
@@ -0,0 +1,164 @@
+package ghosttohugo
+
+import "testing"
+
+func Test_htmlMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"empty", "", ""},
+		{"text", "hello", "hello\n"},
+		{
+			"paragraphs",
+			"<p>one <strong>two</strong></p>\n<p><em>three </em>four</p>",
+			"one **two**\n\n_three_ four\n",
+		},
+		{
+			"ghost_markdown_card",
+			"<div class=\"kg-card-markdown\"><h2>Title</h2><p>text</p></div>",
+			"## Title\n\ntext\n",
+		},
+		{
+			"link_and_image",
+			`<p><a href="https://gohugo.io">Hugo</a> <img src="/content/images/a.png" alt="a"></p>`,
+			"[Hugo](https://gohugo.io) ![a](/images/a.png)\n",
+		},
+		{
+			"escape",
+			"<p>2*3 = snake_case</p>",
+			"2\\*3 = snake\\_case\n",
+		},
+		{
+			"escape_block_start",
+			"<p>1984. A year</p><p># x</p><p>+ x</p><p>&gt; x</p><p>- x</p><p>2) x</p>",
+			"1984\\. A year\n\n\\# x\n\n\\+ x\n\n\\> x\n\n\\- x\n\n2\\) x\n",
+		},
+		{
+			"escape_block_start_lines",
+			"<p>one<br>1. two<br># three</p>",
+			"one  \n1\\. two  \n\\# three\n",
+		},
+		{
+			"escape_block_start_list_item",
+			"<ul><li>1984. A year</li></ul>",
+			"* 1984\\. A year\n",
+		},
+		{
+			"no_escape_inside_line",
+			"<p>A year, 1984. # + - &gt;</p>",
+			"A year, 1984. # + - >\n",
+		},
+		{
+			"nested_list",
+			"<ol><li>one<ul><li>sub</li></ul></li><li>two</li></ol>",
+			"1. one\n    * sub\n2. two\n",
+		},
+		{
+			"loose_list",
+			"<ul><li><p>one</p><p>more</p></li><li><p>two</p></li></ul>",
+			"* one\n\n    more\n* two\n",
+		},
+		{
+			"blockquote",
+			"<blockquote><p>one</p><p>two</p></blockquote>",
+			"> one\n>\n> two\n",
+		},
+		{
+			"pre_language",
+			"<pre><code class=\"language-go\">fmt.Println()\n</code></pre>",
+			"```go\nfmt.Println()\n```\n",
+		},
+		{
+			"figure_image",
+			`<figure class="kg-card kg-image-card"><img src="/content/images/a.png"><figcaption>cap</figcaption></figure>`,
+			"{{< figure src=\"/images/a.png\" caption=\"cap\" >}}\n",
+		},
+		{
+			"figure_image_full",
+			`<figure class="kg-card kg-image-card kg-width-full kg-card-hascaption">` +
+				`<a href="/big/"><img src="/content/images/a.png" alt="A" title="T"></a>` +
+				`<figcaption>By <a href="/jane/">Jane_D</a></figcaption></figure>`,
+			"{{< figure src=\"/images/a.png\" alt=\"A\" title=\"T\" link=\"/big/\"" +
+				" caption=`By [Jane\\_D](/jane/)` class=\"kg-width-full\" >}}\n",
+		},
+		{
+			"figure_gallery",
+			`<figure class="kg-card kg-gallery-card"><div class="kg-gallery-container">` +
+				`<img src="/content/images/a.png" width="600" height="400" alt="A">` +
+				`<img src="/content/images/b.png" width="600" height="400">` +
+				`</div><figcaption><em>Two</em></figcaption></figure>`,
+			"{{< gallery caption=\"_Two_\" >}}\n" +
+				"{{< galleryImg src=\"/images/a.png\" width=\"600\" height=\"400\" alt=\"A\" >}}" +
+				"{{< galleryImg src=\"/images/b.png\" width=\"600\" height=\"400\" >}}" +
+				"{{< /gallery >}}\n",
+		},
+		{
+			"figure_bookmark",
+			`<figure class="kg-card kg-bookmark-card"><a class="kg-bookmark-container" href="https://gohugo.io/">` +
+				`<div class="kg-bookmark-content"><div class="kg-bookmark-title">Hugo</div>` +
+				`<div class="kg-bookmark-description">Fast sites</div><div class="kg-bookmark-metadata">` +
+				`<img class="kg-bookmark-icon" src="https://gohugo.io/favicon.ico">` +
+				`<span class="kg-bookmark-author">Hugo Authors</span><span class="kg-bookmark-publisher">Hugo</span>` +
+				`</div></div><div class="kg-bookmark-thumbnail"><img src="https://gohugo.io/thumb.png"></div>` +
+				`</a><figcaption>A <b>link</b></figcaption></figure>`,
+			"{{< bookmark url=\"https://gohugo.io/\" title=\"Hugo\" description=\"Fast sites\"" +
+				" icon=\"https://gohugo.io/favicon.ico\" author=\"Hugo Authors\" publisher=\"Hugo\"" +
+				" thumbnail=\"https://gohugo.io/thumb.png\" caption=\"A **link**\" >}}\n",
+		},
+		{
+			"figure_other_card",
+			`<figure class="kg-card kg-embed-card"><img src="/a.png"><img src="/b.png"></figure>`,
+			"<figure class=\"kg-card kg-embed-card\"><img src=\"/a.png\"/><img src=\"/b.png\"/></figure>\n",
+		},
+		{
+			"table",
+			"<table><thead><tr><th>a</th><th style=\"text-align: right\">b</th></tr></thead>" +
+				"<tbody><tr><td>1</td><td>x|y</td></tr></tbody></table>",
+			"| a | b |\n| --- | ---: |\n| 1 | x\\|y |\n",
+		},
+		{
+			"complex_table",
+			"<table><tr><td colspan=\"2\">a</td></tr></table>",
+			"<table><tbody><tr><td colspan=\"2\">a</td></tr></tbody></table>\n",
+		},
+		{
+			"raw_passthrough",
+			`<p>x<sup>2</sup></p><iframe src="https://example.com"></iframe>`,
+			"x<sup>2</sup>\n\n<iframe src=\"https://example.com\"></iframe>\n",
+		},
+		{
+			"card_markers",
+			"<!--kg-card-begin: html--><p>text</p><!--kg-card-end: html-->",
+			"text\n",
+		},
+		{"hr", "<p>a</p><hr><p>b</p>", "a\n\n---\n\nb\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlMarkdown(tt.html); got != tt.want {
+				t.Errorf("htmlMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_htmlPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"empty", "", ""},
+		{"text", " A  cat\n", "A cat"},
+		{"markup", "A <b>black</b> &amp; white cat", "A black & white cat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlPlainText(tt.html); got != tt.want {
+				t.Errorf("htmlPlainText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
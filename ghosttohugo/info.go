@@ -11,13 +11,31 @@ type meta struct {
 }
 
 type user struct {
-	ID   json.RawMessage `json:"id"`
-	Name string          `json:"name"`
+	ID           json.RawMessage `json:"id"`
+	Name         string          `json:"name"`
+	Slug         string          `json:"slug"`
+	Bio          string          `json:"bio"`
+	Location     string          `json:"location"`
+	Website      string          `json:"website"`
+	Twitter      string          `json:"twitter"`
+	Facebook     string          `json:"facebook"`
+	ProfileImage string          `json:"profile_image"`
+	CoverImage   string          `json:"cover_image"`
+	Image        string          `json:"image"` // Ghost 0.x profile image
+	Cover        string          `json:"cover"` // Ghost 0.x cover image
 }
 
 type tag struct {
-	ID   json.RawMessage `json:"id"`
-	Name string          `json:"name"`
+	ID              json.RawMessage `json:"id"`
+	Name            string          `json:"name"`
+	Slug            string          `json:"slug"`
+	Description     string          `json:"description"`
+	FeatureImage    string          `json:"feature_image"`
+	Image           string          `json:"image"` // Ghost 0.x feature image
+	MetaTitle       string          `json:"meta_title"`
+	MetaDescription string          `json:"meta_description"`
+	AccentColor     string          `json:"accent_color"`
+	Visibility      string          `json:"visibility"`
 }
 
 type posttag struct {
@@ -27,22 +45,77 @@ type posttag struct {
 	SortOrder int             `json:"sort_order,omitempty"`
 }
 
+type postauthor struct {
+	ID        json.RawMessage `json:"id"`
+	PostID    json.RawMessage `json:"post_id"`
+	AuthorID  json.RawMessage `json:"author_id"`
+	SortOrder int             `json:"sort_order,omitempty"`
+}
+
+// postRef is the part of a post needed to link to it before the posts are
+// converted
+type postRef struct {
+	ID          json.RawMessage `json:"id"`
+	Slug        string          `json:"slug"`
+	Page        json.RawMessage `json:"page"`
+	Status      string          `json:"status"`
+	Featured    json.RawMessage `json:"featured"`
+	AuthorID    json.RawMessage `json:"author_id"`
+	PublishedAt json.RawMessage `json:"published_at"`
+	CreatedAt   json.RawMessage `json:"created_at"`
+}
+
+func (r postRef) post() post {
+	return post{
+		ID:          r.ID,
+		Slug:        r.Slug,
+		Page:        r.Page,
+		Status:      r.Status,
+		Featured:    r.Featured,
+		AuthorID:    r.AuthorID,
+		PublishedAt: r.PublishedAt,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// postMeta holds the SEO and social overrides of a post, kept apart from the
+// post since Ghost 3
+type postMeta struct {
+	ID                  json.RawMessage `json:"id"`
+	PostID              json.RawMessage `json:"post_id"`
+	MetaTitle           string          `json:"meta_title"`
+	MetaDescription     string          `json:"meta_description"`
+	OGImage             string          `json:"og_image"`
+	OGTitle             string          `json:"og_title"`
+	OGDescription       string          `json:"og_description"`
+	TwitterImage        string          `json:"twitter_image"`
+	TwitterTitle        string          `json:"twitter_title"`
+	TwitterDescription  string          `json:"twitter_description"`
+	EmailSubject        string          `json:"email_subject"`
+	FeatureImageAlt     string          `json:"feature_image_alt"`
+	FeatureImageCaption string          `json:"feature_image_caption"`
+}
+
 type setting struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
 
 type data struct {
-	Users    []user    `json:"users"`
-	Tags     []tag     `json:"tags"`
-	PostTags []posttag `json:"posts_tags"`
-	Settings []setting `json:"settings"`
+	Users       []user       `json:"users"`
+	Tags        []tag        `json:"tags"`
+	PostTags    []posttag    `json:"posts_tags"`
+	PostAuthors []postauthor `json:"posts_authors"`
+	Settings    []setting    `json:"settings"`
+	Posts       []postRef    `json:"posts"`
+	PostsMeta   []postMeta   `json:"posts_meta"`
 }
 
 type info struct {
 	Meta     meta `json:"meta"`
 	Data     data `json:"data"`
 	settings map[string]string
+	meta     map[string]postMeta // posts_meta by post id
 }
 
 func (c *Converter) decodeInfo(r io.Reader) error {
@@ -70,6 +143,13 @@ func (c *Converter) decodeInfo(r io.Reader) error {
 			c.info.settings[setting.Key] = setting.Value
 		}
 
+		if len(c.info.Data.PostsMeta) > 0 {
+			c.info.meta = make(map[string]postMeta)
+			for _, meta := range c.info.Data.PostsMeta {
+				c.info.meta[string(meta.PostID)] = meta
+			}
+		}
+
 	}
 
 	return nil
@@ -50,7 +50,7 @@ func TestConverter_decodeInfo(t *testing.T) {
 			info{
 				Data: data{
 					Users: []user{
-						{json.RawMessage("1234"), "username"},
+						{ID: json.RawMessage("1234"), Name: "username"},
 					},
 				},
 				settings: make(map[string]string),
@@ -66,8 +66,8 @@ func TestConverter_decodeInfo(t *testing.T) {
 			info{
 				Data: data{
 					Users: []user{
-						{json.RawMessage("1234"), "username1"},
-						{json.RawMessage("4321"), "username2"},
+						{ID: json.RawMessage("1234"), Name: "username1"},
+						{ID: json.RawMessage("4321"), Name: "username2"},
 					},
 				},
 				settings: make(map[string]string),
@@ -82,7 +82,7 @@ func TestConverter_decodeInfo(t *testing.T) {
 			info{
 				Data: data{
 					Tags: []tag{
-						{json.RawMessage("1234"), "tagname"},
+						{ID: json.RawMessage("1234"), Name: "tagname"},
 					},
 				},
 				settings: make(map[string]string),
@@ -98,8 +98,8 @@ func TestConverter_decodeInfo(t *testing.T) {
 			info{
 				Data: data{
 					Tags: []tag{
-						{json.RawMessage("1234"), "tagname1"},
-						{json.RawMessage("4321"), "tagname2"},
+						{ID: json.RawMessage("1234"), Name: "tagname1"},
+						{ID: json.RawMessage("4321"), Name: "tagname2"},
 					},
 				},
 				settings: make(map[string]string),
@@ -107,6 +107,61 @@ func TestConverter_decodeInfo(t *testing.T) {
 			false,
 		},
 
+		// Posts tests
+		{
+			"posts",
+			`{"db":[{"data":{"posts": [
+				{"id": 1, "slug": "a-post", "title": "A post", "page": 0},
+				{"id": 2, "slug": "about", "page": true}
+			]}}]}`,
+			info{
+				Data: data{
+					Posts: []postRef{
+						{
+							ID:   json.RawMessage("1"),
+							Slug: "a-post",
+							Page: json.RawMessage("0"),
+						},
+						{
+							ID:   json.RawMessage("2"),
+							Slug: "about",
+							Page: json.RawMessage("true"),
+						},
+					},
+				},
+				settings: make(map[string]string),
+			},
+			false,
+		},
+
+		// PostsMeta tests
+		{
+			"posts_meta",
+			`{"db":[{"data":{"posts_meta": [
+				{"id": 1, "post_id": 10, "og_title": "OG"}
+			]}}]}`,
+			info{
+				Data: data{
+					PostsMeta: []postMeta{
+						{
+							ID:      json.RawMessage("1"),
+							PostID:  json.RawMessage("10"),
+							OGTitle: "OG",
+						},
+					},
+				},
+				settings: make(map[string]string),
+				meta: map[string]postMeta{
+					"10": {
+						ID:      json.RawMessage("1"),
+						PostID:  json.RawMessage("10"),
+						OGTitle: "OG",
+					},
+				},
+			},
+			false,
+		},
+
 		// PostTags tests
 		{
 			"singe_post_tag",
@@ -152,6 +207,34 @@ func TestConverter_decodeInfo(t *testing.T) {
 			},
 			false,
 		},
+
+		// PostAuthors tests
+		{
+			"multiple_post_authors",
+			`{"db":[{"data":{"posts_authors": [
+				{"id": 1, "post_id": 4321, "author_id": 5432, "sort_order": 1},
+				{"id": 2, "post_id": 4321, "author_id": 6666}
+			]}}]}`,
+			info{
+				Data: data{
+					PostAuthors: []postauthor{
+						{
+							ID:        json.RawMessage("1"),
+							PostID:    json.RawMessage("4321"),
+							AuthorID:  json.RawMessage("5432"),
+							SortOrder: 1,
+						},
+						{
+							ID:       json.RawMessage("2"),
+							PostID:   json.RawMessage("4321"),
+							AuthorID: json.RawMessage("6666"),
+						},
+					},
+				},
+				settings: make(map[string]string),
+			},
+			false,
+		},
 		// TODO(joshua): add tests for settings
 	}
 	for _, tt := range tests {
@@ -0,0 +1,257 @@
+package ghosttohugo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jbarone/mobiledoc"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Text format flags used by the Lexical editor on text nodes
+const (
+	lexicalBold = 1 << iota
+	lexicalItalic
+	lexicalStrikethrough
+	lexicalUnderline
+	lexicalCode
+	lexicalSubscript
+	lexicalSuperscript
+	lexicalHighlight
+)
+
+// lexicalCards maps Koenig card node types to the renderers shared with
+// mobiledoc. Lexical card nodes carry the same fields as the mobiledoc card
+// payloads, so the node itself is handed over as the payload.
+var lexicalCards = map[string]mobiledoc.Card{
+	"markdown":       cardMarkdown,
+	"horizontalrule": cardHR,
+	"image":          cardImage,
+	"codeblock":      cardCode,
+	"embed":          cardEmbed,
+	"gallery":        cardGallery,
+	"html":           cardHTML,
+	"bookmark":       lexicalBookmark,
+	"callout":        cardCallout,
+	"toggle":         cardToggle,
+	"button":         cardButton,
+	"header":         cardHeader,
+	"audio":          cardAudio,
+	"video":          cardVideo,
+	"file":           cardFile,
+	"paywall":        cardPaywall,
+}
+
+func (p post) lexicalMarkdown(embeds map[string]string) string {
+	if p.Lexical == "" {
+		return ""
+	}
+
+	var doc struct {
+		Root map[string]interface{} `json:"root"`
+	}
+	if err := json.Unmarshal([]byte(p.Lexical), &doc); err != nil {
+		jww.ERROR.Printf("error rendering post %s (%v)\n", p.ID, err)
+		return ""
+	}
+
+	cards := make(map[string]mobiledoc.Card, len(lexicalCards))
+	for t, card := range lexicalCards {
+		cards[t] = card
+	}
+	cards["embed"] = embedCard(embeds)
+
+	var buf bytes.Buffer
+	for _, child := range lexicalChildren(doc.Root) {
+		lexicalBlock(&buf, child, cards)
+	}
+
+	return buf.String()
+}
+
+// lexicalBookmark adapts a Lexical bookmark node, which keeps the url next
+// to the metadata instead of inside it, to the mobiledoc bookmark payload.
+func lexicalBookmark(payload interface{}) string {
+	m, ok := payload.(map[string]interface{})
+	if !ok {
+		jww.ERROR.Println("lexicalBookmark: payload not correct type")
+		return ""
+	}
+
+	if metadata, ok := m["metadata"].(map[string]interface{}); ok {
+		if _, ok := metadata["url"]; !ok {
+			metadata["url"] = m["url"]
+		}
+	}
+
+	return cardBookmark(m)
+}
+
+func lexicalType(node map[string]interface{}) string {
+	t, _ := node["type"].(string)
+	return strings.TrimPrefix(t, "extended-")
+}
+
+func lexicalChildren(node map[string]interface{}) []map[string]interface{} {
+	raw, _ := node["children"].([]interface{})
+
+	children := make([]map[string]interface{}, 0, len(raw))
+	for _, r := range raw {
+		if child, ok := r.(map[string]interface{}); ok {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+func lexicalBlock(
+	buf *bytes.Buffer,
+	node map[string]interface{},
+	cards map[string]mobiledoc.Card,
+) {
+	switch t := lexicalType(node); t {
+	case "paragraph":
+		text := lexicalInline(lexicalChildren(node))
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		buf.WriteString(htmlEscapeLines(text))
+		buf.WriteString("\n\n")
+	case "heading":
+		level := 1
+		if tag, ok := node["tag"].(string); ok {
+			fmt.Sscanf(tag, "h%d", &level)
+		}
+		buf.WriteString(strings.Repeat("#", level))
+		buf.WriteString(" ")
+		buf.WriteString(lexicalInline(lexicalChildren(node)))
+		buf.WriteString("\n\n")
+	case "quote", "aside":
+		text := htmlEscapeLines(lexicalInline(lexicalChildren(node)))
+		for _, line := range strings.Split(text, "\n") {
+			buf.WriteString("> ")
+			buf.WriteString(line)
+			buf.WriteString("\n")
+		}
+		buf.WriteString("\n")
+	case "list":
+		lexicalList(buf, node, 0)
+		buf.WriteString("\n")
+	default:
+		card, ok := cards[t]
+		if !ok {
+			jww.ERROR.Printf("unable to locate renderer for lexical node %q\n", t)
+			return
+		}
+		out := card(node)
+		if out == "" {
+			return
+		}
+		buf.WriteString(out)
+		if !strings.HasSuffix(out, "\n") {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("\n")
+	}
+}
+
+func lexicalList(buf *bytes.Buffer, node map[string]interface{}, depth int) {
+	listType, _ := node["listType"].(string)
+	number := 1
+	if start, ok := node["start"].(float64); ok && start > 0 {
+		number = int(start)
+	}
+	indent := strings.Repeat("    ", depth)
+
+	for _, item := range lexicalChildren(node) {
+		var inline, nested []map[string]interface{}
+		for _, child := range lexicalChildren(item) {
+			if lexicalType(child) == "list" {
+				nested = append(nested, child)
+				continue
+			}
+			inline = append(inline, child)
+		}
+
+		if len(inline) > 0 || len(nested) == 0 {
+			buf.WriteString(indent)
+			switch listType {
+			case "number":
+				fmt.Fprintf(buf, "%d. ", number)
+				number++
+			case "check":
+				if checked, _ := item["checked"].(bool); checked {
+					buf.WriteString("- [x] ")
+				} else {
+					buf.WriteString("- [ ] ")
+				}
+			default:
+				buf.WriteString("* ")
+			}
+			buf.WriteString(htmlEscapeLines(lexicalInline(inline)))
+			buf.WriteString("\n")
+		}
+
+		for _, list := range nested {
+			lexicalList(buf, list, depth+1)
+		}
+	}
+}
+
+func lexicalInline(nodes []map[string]interface{}) string {
+	var buf bytes.Buffer
+
+	for _, node := range nodes {
+		switch lexicalType(node) {
+		case "text":
+			text, _ := node["text"].(string)
+			format, _ := node["format"].(float64)
+			buf.WriteString(lexicalText(text, int(format)))
+		case "link", "autolink":
+			url, _ := node["url"].(string)
+			fmt.Fprintf(&buf, "[%s](%s)", lexicalInline(lexicalChildren(node)), url)
+		case "linebreak":
+			buf.WriteString("\n")
+		case "tab":
+			buf.WriteString("\t")
+		default:
+			buf.WriteString(lexicalInline(lexicalChildren(node)))
+		}
+	}
+
+	return buf.String()
+}
+
+// lexicalText wraps text in the markdown (or inline HTML) markers for the
+// given format flags. Lexical text is literal, so it is escaped outside of
+// code.
+func lexicalText(text string, format int) string {
+	if format&lexicalCode != 0 {
+		return wrapSpace(text, "`", "`")
+	}
+	text = htmlEscaper.Replace(text)
+
+	var open, end string
+	for _, f := range []struct {
+		flag      int
+		open, end string
+	}{
+		{lexicalBold, "**", "**"},
+		{lexicalItalic, "_", "_"},
+		{lexicalStrikethrough, "~~", "~~"},
+		{lexicalUnderline, "<u>", "</u>"},
+		{lexicalSubscript, "<sub>", "</sub>"},
+		{lexicalSuperscript, "<sup>", "</sup>"},
+		{lexicalHighlight, "<mark>", "</mark>"},
+	} {
+		if format&f.flag != 0 {
+			open = open + f.open
+			end = f.end + end
+		}
+	}
+
+	return wrapSpace(text, open, end)
+}
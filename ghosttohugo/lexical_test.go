@@ -0,0 +1,179 @@
+package ghosttohugo
+
+import "testing"
+
+func Test_post_lexicalMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		lexical string
+		want    string
+	}{
+		{"empty", "", ""},
+		{"invalid", "{", ""},
+		{
+			"paragraph",
+			`{"root":{"type":"root","children":[
+				{"type":"paragraph","children":[
+					{"type":"text","text":"hello ","format":0},
+					{"type":"text","text":"world ","format":1},
+					{"type":"text","text":"again","format":3}
+				]}
+			]}}`,
+			"hello **world** **_again_**\n\n",
+		},
+		{
+			"paragraph_block_start",
+			`{"root":{"children":[
+				{"type":"paragraph","children":[
+					{"type":"text","text":"1984. A year","format":0},
+					{"type":"linebreak"},
+					{"type":"text","text":"# not a heading","format":0}
+				]}
+			]}}`,
+			"1984\\. A year\n\\# not a heading\n\n",
+		},
+		{
+			"empty_paragraph",
+			`{"root":{"children":[{"type":"paragraph","children":[]}]}}`,
+			"",
+		},
+		{
+			"heading",
+			`{"root":{"children":[
+				{"type":"extended-heading","tag":"h2","children":[
+					{"type":"extended-text","text":"Title","format":0}
+				]}
+			]}}`,
+			"## Title\n\n",
+		},
+		{
+			"quote",
+			`{"root":{"children":[
+				{"type":"quote","children":[
+					{"type":"text","text":"one"},
+					{"type":"linebreak"},
+					{"type":"text","text":"two"}
+				]}
+			]}}`,
+			"> one\n> two\n\n",
+		},
+		{
+			"link",
+			`{"root":{"children":[
+				{"type":"paragraph","children":[
+					{"type":"link","url":"https://gohugo.io","children":[
+						{"type":"text","text":"Hugo","format":16}
+					]}
+				]}
+			]}}`,
+			"[`Hugo`](https://gohugo.io)\n\n",
+		},
+		{
+			"nested_list",
+			`{"root":{"children":[
+				{"type":"list","listType":"number","start":1,"children":[
+					{"type":"listitem","children":[{"type":"text","text":"one"}]},
+					{"type":"listitem","children":[
+						{"type":"list","listType":"bullet","children":[
+							{"type":"listitem","children":[{"type":"text","text":"sub"}]}
+						]}
+					]},
+					{"type":"listitem","children":[{"type":"text","text":"two"}]}
+				]}
+			]}}`,
+			"1. one\n    * sub\n2. two\n\n",
+		},
+		{
+			"code_card",
+			`{"root":{"children":[
+				{"type":"codeblock","code":"fmt.Println()","language":"go"}
+			]}}`,
+			"```go\nfmt.Println()\n```\n\n",
+		},
+		{
+			"code_card_null_language",
+			`{"root":{"children":[
+				{"type":"codeblock","code":"x","language":null}
+			]}}`,
+			"```\nx\n```\n\n",
+		},
+		{
+			"bookmark_card_null_title",
+			`{"root":{"children":[
+				{"type":"bookmark","url":"https://gohugo.io","metadata":{
+					"title":null,"description":"Static sites"
+				}}
+			]}}`,
+			"",
+		},
+		{
+			"image_card",
+			`{"root":{"children":[
+				{"type":"image","src":"/content/images/test.jpg"}
+			]}}`,
+			"{{< figure src=\"/images/test.jpg\" >}}\n\n",
+		},
+		{
+			"bookmark_card",
+			`{"root":{"children":[
+				{"type":"bookmark","url":"https://gohugo.io","metadata":{
+					"title":"Hugo","description":"Static sites"
+				}}
+			]}}`,
+			"{{< bookmark url=\"https://gohugo.io\" title=\"Hugo\"" +
+				" description=\"Static sites\" icon=\"\" author=\"\"" +
+				" publisher=\"\" thumbnail=\"\" caption=\"\" >}}\n\n",
+		},
+		{
+			"callout_card",
+			`{"root":{"children":[
+				{"type":"callout","calloutEmoji":"!","calloutText":"<p>Hi</p>",
+				"backgroundColor":"white"}
+			]}}`,
+			"{{< callout emoji=\"!\" color=\"white\" >}}\nHi\n{{< /callout >}}\n\n",
+		},
+		{
+			"unknown_card",
+			`{"root":{"children":[{"type":"unknown"}]}}`,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := post{Lexical: tt.lexical}
+			if got := p.lexicalMarkdown(nil); got != tt.want {
+				t.Errorf("post.lexicalMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lexicalText(t *testing.T) {
+	type args struct {
+		text   string
+		format int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"plain", args{"text", 0}, "text"},
+		{"bold", args{"text", lexicalBold}, "**text**"},
+		{"italic_spaces", args{" text ", lexicalItalic}, " _text_ "},
+		{"strike", args{"text", lexicalStrikethrough}, "~~text~~"},
+		{"escaped", args{"2*3*4 and snake_case", 0}, `2\*3\*4 and snake\_case`},
+		{"escaped_bold", args{"[x]", lexicalBold}, `**\[x\]**`},
+		{"code", args{"text", lexicalCode | lexicalBold}, "`text`"},
+		{"code_unescaped", args{"a_b*c", lexicalCode}, "`a_b*c`"},
+		{"underline", args{"text", lexicalUnderline}, "<u>text</u>"},
+		{"whitespace", args{"  ", lexicalBold}, "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lexicalText(tt.args.text, tt.args.format); got != tt.want {
+				t.Errorf("lexicalText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
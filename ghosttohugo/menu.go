@@ -0,0 +1,91 @@
+package ghosttohugo
+
+import (
+	"encoding/json"
+	"strings"
+
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// navItem is an entry of the Ghost navigation settings
+type navItem struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// menus converts the Ghost navigation and secondary_navigation settings into
+// the main and footer menus of the Hugo config.
+func (c Converter) menus() map[string]interface{} {
+	menus := make(map[string]interface{})
+	for setting, menu := range map[string]string{
+		"navigation":           "main",
+		"secondary_navigation": "footer",
+	} {
+		if entries := c.menu(setting); len(entries) > 0 {
+			menus[menu] = entries
+		}
+	}
+	return menus
+}
+
+// menu returns the menu entries for a Ghost navigation setting, weighted in
+// the order Ghost shows them.
+func (c Converter) menu(setting string) []interface{} {
+	raw := c.info.settings[setting]
+	if raw == "" {
+		return nil
+	}
+
+	var items []navItem
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		jww.WARN.Printf("unable to read the %s setting (%v)\n", setting, err)
+		return nil
+	}
+
+	var entries []interface{}
+	for _, item := range items {
+		if item.Label == "" {
+			continue
+		}
+		entries = append(entries, map[string]interface{}{
+			"name":   item.Label,
+			"url":    c.contentURL(item.URL),
+			"weight": len(entries) + 1,
+		})
+	}
+	return entries
+}
+
+// contentURL rewrites a link to the Ghost site into a site relative path,
+// pointing at the converted page when there is one.
+func (c Converter) contentURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	p, ok := c.relativeURL(raw)
+	if !ok {
+		if !strings.HasPrefix(raw, "/") || strings.HasPrefix(raw, "//") {
+			return raw
+		}
+		p = raw
+	}
+
+	rest := ""
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p, rest = p[:i], p[i:]
+	}
+
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	switch {
+	case len(segments) == 2 && segments[0] == "tag":
+		return c.termURL("tag", segments[1]) + rest
+	case len(segments) == 2 && segments[0] == "author":
+		return c.termURL("author", segments[1]) + rest
+	case p != "/":
+		for _, page := range c.pages {
+			if strings.Trim(page.ghost, "/") == strings.Trim(p, "/") {
+				return page.hugo + rest
+			}
+		}
+	}
+
+	return p + rest
+}
@@ -0,0 +1,85 @@
+package ghosttohugo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConverter_contentURL(t *testing.T) {
+	c := Converter{
+		siteURLs: []string{"https://ourblog.com/"},
+		info: info{Data: data{Posts: []postRef{
+			{Slug: "hello", Page: json.RawMessage("false")},
+			{Slug: "about", Page: json.RawMessage("true")},
+		}}},
+	}
+	c.indexPosts()
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"home", "https://ourblog.com/", "/"},
+		{"placeholder_home", "__GHOST_URL__/", "/"},
+		{"page", "https://ourblog.com/about/", "/about/"},
+		{"post", "__GHOST_URL__/hello/", "/post/hello/"},
+		{"relative_post", "/hello", "/post/hello/"},
+		{"fragment", "/hello/#comments", "/post/hello/#comments"},
+		{"tag", "https://www.ourblog.com/tag/news/", "/tags/news/"},
+		{"author", "/author/jo/", "/authors/jo/"},
+		{"unknown", "https://ourblog.com/rss/", "/rss/"},
+		{"offsite", "https://twitter.com/ghost", "https://twitter.com/ghost"},
+		{"anchor", "#subscribe", "#subscribe"},
+		{"protocol_relative", "//example.com/a/", "//example.com/a/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.contentURL(tt.raw); got != tt.want {
+				t.Errorf("Converter.contentURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_menus(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     map[string]interface{}
+	}{
+		{"none", map[string]string{}, map[string]interface{}{}},
+		{
+			"invalid",
+			map[string]string{"navigation": "not json"},
+			map[string]interface{}{},
+		},
+		{
+			"both",
+			map[string]string{
+				"navigation": `[{"label":"Home","url":"/"},` +
+					`{"label":"","url":"/x/"},` +
+					`{"label":"About","url":"/about/"}]`,
+				"secondary_navigation": `[{"label":"Data","url":"/privacy/"}]`,
+			},
+			map[string]interface{}{
+				"main": []interface{}{
+					map[string]interface{}{"name": "Home", "url": "/", "weight": 1},
+					map[string]interface{}{"name": "About", "url": "/about/", "weight": 2},
+				},
+				"footer": []interface{}{
+					map[string]interface{}{"name": "Data", "url": "/privacy/", "weight": 1},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{info: info{settings: tt.settings}}
+			if got := c.menus(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Converter.menus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,39 +7,57 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gohugoio/hugo/helpers"
-	"github.com/gohugoio/hugo/parser"
 	"github.com/jbarone/mobiledoc"
 	jww "github.com/spf13/jwalterweatherman"
 )
 
 type post struct {
 	ID              json.RawMessage `json:"id"`
+	UUID            string          `json:"uuid"`
 	Title           string          `json:"title"`
 	Slug            string          `json:"slug"`
 	Content         string          `json:"markdown"`
 	Plain           string          `json:"plaintext"`
 	MobileDoc       string          `json:"mobiledoc,omitempty"`
+	Lexical         string          `json:"lexical,omitempty"`
+	HTML            string          `json:"html,omitempty"`
 	Image           string          `json:"image"`
 	FeaturedImage   string          `json:"feature_image,omitempty"`
 	Page            json.RawMessage `json:"page"`
+	Featured        json.RawMessage `json:"featured"`
 	Status          string          `json:"status"`
+	Visibility      string          `json:"visibility"`
 	MetaDescription string          `json:"meta_description"`
 	AuthorID        json.RawMessage `json:"author_id"`
 	PublishedAt     json.RawMessage `json:"published_at"`
 	CreatedAt       json.RawMessage `json:"created_at"`
+	UpdatedAt       json.RawMessage `json:"updated_at"`
 	Summary         string          `json:"custom_excerpt"`
+	CanonicalURL    string          `json:"canonical_url"`
 
-	Published time.Time
-	Created   time.Time
-	Author    string
-	Tags      []string
+	Published  time.Time
+	Created    time.Time
+	Updated    time.Time
+	Author     string
+	Authors    []string
+	Tags       []string
+	Categories []string
+	Meta       postMeta
+
+	InternalTags  []string
+	InternalSlugs []string // Ghost slugs of the internal tags, for filters
 }
 
 func (p post) isDraft() bool {
 	return strings.ToLower(p.Status) == "draft"
 }
 
+// isScheduled reports whether the post is set to be published at a later
+// date
+func (p post) isScheduled() bool {
+	return strings.ToLower(p.Status) == "scheduled"
+}
+
 func (p post) isPage() bool {
 	return parseBool(p.Page)
 }
@@ -53,66 +71,188 @@ func (p post) frontMatter() map[string]interface{} {
 	case false:
 		metadata["date"] = p.Published
 	}
+	if p.isScheduled() && !p.Published.IsZero() {
+		metadata["publishDate"] = p.Published
+	}
+	if !p.Updated.IsZero() {
+		metadata["lastmod"] = p.Updated
+	}
 	metadata["title"] = p.Title
 	metadata["draft"] = p.isDraft()
 	metadata["slug"] = p.Slug
 	metadata["description"] = p.MetaDescription
+	if p.MetaDescription == "" {
+		metadata["description"] = p.Meta.MetaDescription
+	}
 	if p.Image != "" {
 		metadata["image"] = stripContentFolder(p.Image)
 	} else if p.FeaturedImage != "" {
 		metadata["image"] = stripContentFolder(p.FeaturedImage)
 	}
+	if images := p.images(); len(images) > 0 {
+		metadata["images"] = images
+	}
+	for key, value := range map[string]string{
+		"meta_title":            p.Meta.MetaTitle,
+		"og_title":              p.Meta.OGTitle,
+		"og_description":        p.Meta.OGDescription,
+		"twitter_title":         p.Meta.TwitterTitle,
+		"twitter_description":   p.Meta.TwitterDescription,
+		"email_subject":         p.Meta.EmailSubject,
+		"feature_image_alt":     htmlPlainText(p.Meta.FeatureImageAlt),
+		"feature_image_caption": strings.TrimSpace(htmlMarkdown(p.Meta.FeatureImageCaption)),
+	} {
+		if value != "" {
+			setKey(metadata, "params."+key, value)
+		}
+	}
 	if len(p.Tags) > 0 {
 		metadata["tags"] = p.Tags
-		metadata["categories"] = p.Tags
+	}
+	if len(p.Categories) > 0 {
+		metadata["categories"] = p.Categories
 	}
 	if p.Author != "" {
 		metadata["author"] = p.Author
 	}
+	if len(p.Authors) > 0 {
+		metadata["authors"] = p.Authors
+	}
 	if p.Summary != "" {
 		metadata["summary"] = p.Summary
 	}
+	if p.CanonicalURL != "" {
+		metadata["canonical"] = p.CanonicalURL
+	}
+	if p.Visibility != "" {
+		metadata["visibility"] = p.Visibility
+	}
 
 	return metadata
 }
 
+// images lists the images used for the social previews of the post, as read
+// by the opengraph and twitter_cards templates of Hugo: the OpenGraph and
+// Twitter images, then the feature image.
+func (p post) images() []string {
+	var images []string
+	for _, image := range []string{
+		p.Meta.OGImage,
+		p.Meta.TwitterImage,
+		p.FeaturedImage,
+		p.Image,
+	} {
+		if image == "" {
+			continue
+		}
+		image = stripContentFolder(image)
+		seen := false
+		for _, i := range images {
+			seen = seen || i == image
+		}
+		if !seen {
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// markdown renders the content of the post. embeds overrides the shortcodes
+// of the embed providers.
+func (p post) markdown(embeds map[string]string) string {
+	switch {
+	case p.Lexical != "":
+		return p.lexicalMarkdown(embeds)
+	case p.Content != "":
+		return p.Content
+	case p.MobileDoc != "":
+		return p.mobiledocMarkdown(embeds)
+	case p.HTML != "":
+		return htmlMarkdown(p.HTML)
+	default:
+		return p.Plain
+	}
+}
+
 func (c *Converter) writePost(p post) error {
 	jww.DEBUG.Printf("converting: %s", p.Title)
 	path := filepath.Join(c.path, "content")
-	switch p.isPage() {
+	if !p.isPage() {
+		section := "post"
+		if col, ok := c.collection(p); ok {
+			section = col.section
+		}
+		path = filepath.Join(path, section)
+	}
+	switch c.bundle {
 	case true:
-		path = filepath.Join(path, p.Slug+".md")
+		path = filepath.Join(path, p.Slug, "index.md")
 	case false:
-		path = filepath.Join(path, "post", p.Slug+".md")
+		path = filepath.Join(path, p.Slug+".md")
 	}
 
-	buf := bytes.NewBuffer(nil)
-	if err := parser.InterfaceToFrontMatter(p.frontMatter(), c.kind, buf); err != nil {
-		return err
+	metadata := p.frontMatter()
+	c.applyInternalTags(metadata, p.InternalTags)
+	if c.identifier != "" && p.UUID != "" {
+		setKey(metadata, c.identifier, p.UUID)
 	}
-	if _, err := buf.Write([]byte("\n\n")); err != nil {
-		return err
+	if col, ok := c.collection(p); ok && col.permalink != "" {
+		if _, ok := hugoPermalink(col.permalink); !ok {
+			metadata["url"] = c.hugoURL(p)
+		}
+	}
+	aliases := append(c.postAliases(p), c.redirectAliases[p.Slug]...)
+	if len(aliases) > 0 {
+		metadata["aliases"] = aliases
 	}
 
+	return c.writeContent(path, p.Slug, metadata, c.applyPaywall(p, p.markdown(c.embeds)))
+}
+
+// isGated reports whether the post is only visible to members of the site
+func (p post) isGated() bool {
+	return p.Visibility != "" && p.Visibility != "public"
+}
+
+// applyPaywall applies the paywall policy to the content of the post. Ghost
+// ignores the paywall of public posts, so for them it is only removed. A
+// post that is not public and has no paywall has no public preview.
+func (c Converter) applyPaywall(p post, content string) string {
+	var preview, rest string
+	switch i := strings.Index(content, paywallMarker); {
+	case i >= 0:
+		preview = strings.Trim(content[:i], "\n")
+		rest = strings.Trim(content[i+len(paywallMarker):], "\n")
+	case !p.isGated() || c.paywall == PaywallMore:
+		return content
+	default:
+		rest = strings.Trim(content, "\n")
+	}
+
+	var parts []string
+	if preview != "" {
+		parts = append(parts, preview)
+	}
 	switch {
-	case p.Content != "":
-		if _, err := buf.Write([]byte(p.Content)); err != nil {
-			return err
-		}
-	case p.MobileDoc != "":
-		if _, err := buf.Write([]byte(p.mobiledocMarkdown())); err != nil {
-			return err
-		}
+	case !p.isGated():
+		parts = append(parts, rest)
+	case c.paywall == PaywallTruncate:
+	case c.paywall == PaywallMembers:
+		parts = append(parts, "{{% members %}}\n"+rest+"\n{{% /members %}}")
 	default:
-		if _, err := buf.Write([]byte(p.Plain)); err != nil {
-			return err
-		}
+		parts = append(parts, "<!--more-->", rest)
 	}
 
-	return helpers.WriteToDisk(path, bytes.NewReader(buf.Bytes()), c.site.Fs.Source)
+	var out string
+	for _, part := range parts {
+		if part != "" {
+			out += part + "\n\n"
+		}
+	}
+	return strings.TrimSuffix(out, "\n")
 }
 
-func (p post) mobiledocMarkdown() string {
+func (p post) mobiledocMarkdown(embeds map[string]string) string {
 	if p.MobileDoc == "" {
 		return ""
 	}
@@ -128,10 +268,18 @@ func (p post) mobiledocMarkdown() string {
 		WithCard("hr", cardHR).
 		WithCard("image", cardImage).
 		WithCard("code", cardCode).
-		WithCard("embed", cardEmbed).
+		WithCard("embed", embedCard(embeds)).
 		WithCard("gallery", cardGallery).
 		WithCard("html", cardHTML).
-		WithCard("bookmark", cardBookmark)
+		WithCard("bookmark", cardBookmark).
+		WithCard("callout", cardCallout).
+		WithCard("toggle", cardToggle).
+		WithCard("button", cardButton).
+		WithCard("header", cardHeader).
+		WithCard("audio", cardAudio).
+		WithCard("video", cardVideo).
+		WithCard("file", cardFile).
+		WithCard("paywall", cardPaywall)
 
 	err := md.Render(&buf)
 	if err != nil {
@@ -2,7 +2,14 @@ package ghosttohugo
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
 )
 
 func Test_post_isDraft(t *testing.T) {
@@ -13,6 +20,7 @@ func Test_post_isDraft(t *testing.T) {
 	}{
 		{"yes", "draft", true},
 		{"no", "published", false},
+		{"scheduled", "scheduled", false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -50,3 +58,232 @@ func Test_post_isPage(t *testing.T) {
 		})
 	}
 }
+
+func Test_post_frontMatter_dates(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	published := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		p    post
+		want map[string]interface{}
+	}{
+		{
+			"draft",
+			post{Status: "draft", Created: created},
+			map[string]interface{}{"date": created, "draft": true},
+		},
+		{
+			"published",
+			post{Status: "published", Created: created, Published: published, Updated: updated},
+			map[string]interface{}{"date": published, "lastmod": updated, "draft": false},
+		},
+		{
+			"scheduled",
+			post{Status: "scheduled", Created: created, Published: published, Updated: updated},
+			map[string]interface{}{
+				"date":        published,
+				"publishDate": published,
+				"lastmod":     updated,
+				"draft":       false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]interface{})
+			for key, value := range tt.p.frontMatter() {
+				switch key {
+				case "date", "publishDate", "lastmod", "draft":
+					got[key] = value
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("post.frontMatter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_post_frontMatter_meta(t *testing.T) {
+	tests := []struct {
+		name string
+		p    post
+		want map[string]interface{}
+	}{
+		{"empty", post{}, map[string]interface{}{"description": ""}},
+		{
+			"post_description",
+			post{MetaDescription: "post", Meta: postMeta{MetaDescription: "meta"}},
+			map[string]interface{}{"description": "post"},
+		},
+		{
+			"feature_image",
+			post{FeaturedImage: "/content/images/a.jpg"},
+			map[string]interface{}{
+				"description": "",
+				"images":      []string{"/images/a.jpg"},
+			},
+		},
+		{
+			"posts_meta",
+			post{
+				FeaturedImage: "/content/images/a.jpg",
+				Meta: postMeta{
+					MetaTitle:           "Meta title",
+					MetaDescription:     "Meta description",
+					OGImage:             "/content/images/og.jpg",
+					OGTitle:             "OG title",
+					OGDescription:       "OG description",
+					TwitterImage:        "/content/images/a.jpg",
+					TwitterTitle:        "Twitter title",
+					TwitterDescription:  "Twitter description",
+					EmailSubject:        "Subject",
+					FeatureImageAlt:     "Alt",
+					FeatureImageCaption: "Caption",
+				},
+			},
+			map[string]interface{}{
+				"description": "Meta description",
+				"images":      []string{"/images/og.jpg", "/images/a.jpg"},
+				"params": map[string]interface{}{
+					"meta_title":            "Meta title",
+					"og_title":              "OG title",
+					"og_description":        "OG description",
+					"twitter_title":         "Twitter title",
+					"twitter_description":   "Twitter description",
+					"email_subject":         "Subject",
+					"feature_image_alt":     "Alt",
+					"feature_image_caption": "Caption",
+				},
+			},
+		},
+		{
+			"feature_image_html",
+			post{Meta: postMeta{
+				FeatureImageAlt:     "A <b>cat</b>",
+				FeatureImageCaption: `Photo by <a href="https://unsplash.com/">Unsplash</a>`,
+			}},
+			map[string]interface{}{
+				"description": "",
+				"params": map[string]interface{}{
+					"feature_image_alt":     "A cat",
+					"feature_image_caption": "Photo by [Unsplash](https://unsplash.com/)",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]interface{})
+			for key, value := range tt.p.frontMatter() {
+				switch key {
+				case "description", "images", "params":
+					got[key] = value
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("post.frontMatter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_writePost_identifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		want       []string
+	}{
+		{"none", "", []string{`canonical = "https://example.com/a/"`}},
+		{"disqus", "disqus_identifier", []string{
+			`canonical = "https://example.com/a/"`,
+			`disqus_identifier = "7c3c1b5a"`,
+		}},
+		{"nested", "params.uuid", []string{"[params]", `uuid = "7c3c1b5a"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{
+				path:       t.TempDir(),
+				kind:       metadecoders.TOML,
+				identifier: tt.identifier,
+			}
+			if err := c.createSite(); err != nil {
+				t.Fatal(err)
+			}
+
+			p := post{
+				Slug:         "a",
+				Status:       "published",
+				UUID:         "7c3c1b5a",
+				CanonicalURL: "https://example.com/a/",
+			}
+			if err := c.writePost(p); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := ioutil.ReadFile(filepath.Join(c.path, "content", "post", "a.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("Converter.writePost() = %s, want %s", data, want)
+				}
+			}
+			if tt.identifier == "" && strings.Contains(string(data), "7c3c1b5a") {
+				t.Errorf("Converter.writePost() = %s, want no uuid", data)
+			}
+		})
+	}
+}
+
+func TestConverter_applyPaywall(t *testing.T) {
+	const content = "Preview\n\n<!--members-only-->\n\nPremium\n"
+	tests := []struct {
+		name       string
+		policy     string
+		visibility string
+		content    string
+		want       string
+	}{
+		{"public_no_paywall", PaywallTruncate, "public", "Text\n", "Text\n"},
+		{"paid_no_paywall", PaywallTruncate, "paid", "Premium\n", ""},
+		{
+			"paid_no_paywall_members",
+			PaywallMembers,
+			"paid",
+			"Premium\n",
+			"{{% members %}}\nPremium\n{{% /members %}}\n",
+		},
+		{"paid_no_paywall_more", PaywallMore, "paid", "Premium\n", "Premium\n"},
+		{"public", PaywallTruncate, "public", content, "Preview\n\nPremium\n"},
+		{"no_visibility", PaywallMembers, "", content, "Preview\n\nPremium\n"},
+		{"truncate", PaywallTruncate, "members", content, "Preview\n"},
+		{
+			"more",
+			PaywallMore,
+			"paid",
+			content,
+			"Preview\n\n<!--more-->\n\nPremium\n",
+		},
+		{
+			"members",
+			PaywallMembers,
+			"tiers",
+			content,
+			"Preview\n\n{{% members %}}\nPremium\n{{% /members %}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{paywall: tt.policy}
+			p := post{Visibility: tt.visibility}
+			if got := c.applyPaywall(p, tt.content); got != tt.want {
+				t.Errorf("applyPaywall() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package ghosttohugo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// redirect is a custom redirect of the Ghost site. from is a regular
+// expression matched against the requested path.
+type redirect struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Permanent bool   `json:"permanent"`
+}
+
+func (r redirect) status() int {
+	if r.Permanent {
+		return 301
+	}
+	return 302
+}
+
+// redirectMatch is a redirect the hosting platforms can express without
+// regular expressions: a single path, or every path under a prefix.
+type redirectMatch struct {
+	path   string
+	prefix bool // path is a prefix, the rest of the path is captured
+	suffix bool // the captured rest of the path is appended to the target
+	to     string
+}
+
+// readRedirects reads a Ghost redirects.json or redirects.yaml file
+func readRedirects(path string) ([]redirect, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		m, err := metadecoders.Default.UnmarshalToMap(data, metadecoders.YAML)
+		if err != nil {
+			return nil, err
+		}
+
+		var redirects []redirect
+		for _, status := range []string{"301", "302"} {
+			rules, _ := m[status].(map[string]interface{})
+			var froms []string
+			for from := range rules {
+				froms = append(froms, from)
+			}
+			sort.Strings(froms)
+			for _, from := range froms {
+				redirects = append(redirects, redirect{
+					From:      from,
+					To:        fmt.Sprint(rules[from]),
+					Permanent: status == "301",
+				})
+			}
+		}
+		return redirects, nil
+	default:
+		var redirects []redirect
+		err := json.Unmarshal(data, &redirects)
+		return redirects, err
+	}
+}
+
+// match turns the redirect into a redirectMatch, if its regular expression
+// is simple enough.
+func (r redirect) match() (redirectMatch, bool) {
+	from := strings.TrimPrefix(r.From, "^")
+	from = strings.TrimSuffix(from, "$")
+	from = strings.TrimSuffix(from, "/?")
+
+	m := redirectMatch{to: r.To}
+	for _, capture := range []string{"(.*)", "(.+)"} {
+		if strings.HasSuffix(from, capture) {
+			from = strings.TrimSuffix(from, capture)
+			m.prefix = true
+		}
+	}
+	from = strings.NewReplacer(`\/`, "/", `\.`, ".", `\-`, "-").Replace(from)
+	if from == "" || strings.ContainsAny(from, `*+?()[]{}|\^$`) {
+		return m, false
+	}
+	if !strings.HasPrefix(from, "/") {
+		from = "/" + from
+	}
+	m.path = from
+
+	if strings.HasSuffix(m.to, "$1") {
+		if !m.prefix {
+			return m, false
+		}
+		m.to = strings.TrimSuffix(m.to, "$1")
+		m.suffix = true
+	}
+	if strings.Contains(m.to, "$") {
+		return m, false
+	}
+
+	return m, true
+}
+
+// writeRedirects converts the Ghost redirects file into aliases of the pages
+// they point at, and into redirect files for Netlify and Cloudflare Pages
+// (static/_redirects), Vercel (vercel.json), Cloudflare bulk redirects
+// (cloudflare-redirects.json) and nginx (nginx-redirects.conf). Redirects
+// that a format can not express are reported.
+func (c *Converter) writeRedirects() error {
+	if c.redirects == "" {
+		return nil
+	}
+
+	redirects, err := readRedirects(c.redirects)
+	if err != nil {
+		return fmt.Errorf("unable to read redirects: %v", err)
+	}
+
+	site, err := url.Parse(c.siteBaseURL())
+	if err != nil {
+		return err
+	}
+
+	var (
+		netlify    bytes.Buffer
+		vercel     []interface{}
+		cloudflare []interface{}
+		nginx      = map[int]*bytes.Buffer{301: {}, 302: {}}
+	)
+	c.redirectAliases = make(map[string][]string)
+	for _, r := range redirects {
+		r.To = c.contentURL(r.To)
+
+		fmt.Fprintf(
+			nginx[r.status()], "    \"%s\" \"%s\";\n", nginxSource(r.From), r.To,
+		)
+
+		m, ok := r.match()
+		if !ok {
+			jww.WARN.Printf(
+				"redirect %s can only be converted for nginx\n", r.From,
+			)
+			continue
+		}
+
+		if slug, ok := c.redirectSlug(r.To); ok && !m.prefix {
+			c.redirectAliases[slug] = append(c.redirectAliases[slug], m.path)
+		}
+
+		source, target := m.path, m.to
+		if m.prefix {
+			source = strings.TrimSuffix(source, "/") + "/*"
+			if m.suffix {
+				target += ":splat"
+			}
+		}
+		fmt.Fprintf(&netlify, "%s %s %d\n", source, target, r.status())
+
+		source, target = m.path, m.to
+		if m.prefix {
+			source = strings.TrimSuffix(source, "/") + "/:path*"
+			if m.suffix {
+				target = strings.TrimSuffix(target, "/") + "/:path*"
+			}
+		}
+		vercel = append(vercel, map[string]interface{}{
+			"source":      source,
+			"destination": target,
+			"permanent":   r.Permanent,
+		})
+
+		target = m.to
+		if strings.HasPrefix(target, "/") {
+			target = site.Scheme + "://" + site.Host + target
+		}
+		cloudflare = append(cloudflare, map[string]interface{}{
+			"redirect": map[string]interface{}{
+				"source_url":           site.Host + m.path,
+				"target_url":           target,
+				"status_code":          r.status(),
+				"subpath_matching":     m.prefix,
+				"preserve_path_suffix": m.suffix,
+			},
+		})
+	}
+
+	var conf bytes.Buffer
+	fmt.Fprintln(&conf, "# Ghost redirects, used from a server block with:")
+	fmt.Fprintln(&conf, "#   if ($ghost_redirect_301) { return 301 $ghost_redirect_301; }")
+	fmt.Fprintln(&conf, "#   if ($ghost_redirect_302) { return 302 $ghost_redirect_302; }")
+	for _, status := range []int{301, 302} {
+		fmt.Fprintf(&conf, "map $uri $ghost_redirect_%d {\n", status)
+		conf.Write(nginx[status].Bytes())
+		fmt.Fprintln(&conf, "}")
+	}
+
+	files := map[string][]byte{
+		filepath.Join("static", "_redirects"): netlify.Bytes(),
+		"nginx-redirects.conf":                conf.Bytes(),
+	}
+	for name, v := range map[string]interface{}{
+		"vercel.json":               map[string]interface{}{"redirects": vercel},
+		"cloudflare-redirects.json": cloudflare,
+	} {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		files[name] = append(data, '\n')
+	}
+	for name, data := range files {
+		err := helpers.WriteToDisk(
+			filepath.Join(c.path, name),
+			bytes.NewReader(data),
+			c.site.Fs.Source,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	jww.INFO.Printf("%d redirect(s) converted\n", len(redirects))
+	return nil
+}
+
+// redirectSlug returns the slug of the post or page a redirect target points
+// at.
+func (c Converter) redirectSlug(to string) (string, bool) {
+	for _, page := range c.pages {
+		if to == page.hugo {
+			return page.slug, true
+		}
+	}
+	return "", false
+}
+
+// nginxSource returns the source of an nginx map entry for a Ghost redirect.
+// Sources that are not plain paths are kept as regular expressions.
+func nginxSource(from string) string {
+	if m, ok := (redirect{From: from}).match(); ok && !m.prefix &&
+		strings.HasPrefix(from, "^") && strings.HasSuffix(from, "$") &&
+		!strings.HasSuffix(from, "/?$") {
+		return m.path
+	}
+	return "~" + from
+}
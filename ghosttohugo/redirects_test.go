@@ -0,0 +1,169 @@
+package ghosttohugo
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+)
+
+func Test_readRedirects(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		want    []redirect
+		wantErr bool
+	}{
+		{
+			"json",
+			"redirects.json",
+			`[{"from":"^/a/$","to":"/b/","permanent":true},{"from":"/c","to":"/d"}]`,
+			[]redirect{
+				{From: "^/a/$", To: "/b/", Permanent: true},
+				{From: "/c", To: "/d"},
+			},
+			false,
+		},
+		{
+			"yaml",
+			"redirects.yaml",
+			"301:\n  /b/: /c/\n  /a/: /c/\n302:\n  ^/x/(.*)$: /y/$1\n",
+			[]redirect{
+				{From: "/a/", To: "/c/", Permanent: true},
+				{From: "/b/", To: "/c/", Permanent: true},
+				{From: "^/x/(.*)$", To: "/y/$1"},
+			},
+			false,
+		},
+		{"invalid", "redirects.json", "{", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := ioutil.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readRedirects(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readRedirects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readRedirects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_redirect_match(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      redirect
+		want   redirectMatch
+		wantOk bool
+	}{
+		{"plain", redirect{From: "/old", To: "/new/"}, redirectMatch{path: "/old", to: "/new/"}, true},
+		{"anchored", redirect{From: `^\/old\.html$`, To: "/new/"}, redirectMatch{path: "/old.html", to: "/new/"}, true},
+		{"trailing_slash", redirect{From: "^/old/?$", To: "/new/"}, redirectMatch{path: "/old", to: "/new/"}, true},
+		{
+			"prefix",
+			redirect{From: "^/blog/(.*)$", To: "/post/$1"},
+			redirectMatch{path: "/blog/", prefix: true, suffix: true, to: "/post/"},
+			true,
+		},
+		{
+			"prefix_single_target",
+			redirect{From: "^/blog/(.+)", To: "/archive/"},
+			redirectMatch{path: "/blog/", prefix: true, to: "/archive/"},
+			true,
+		},
+		{"regex", redirect{From: "^/(a|b)/$", To: "/c/"}, redirectMatch{to: "/c/"}, false},
+		{"capture_without_prefix", redirect{From: "^/a/$", To: "/b/$1"}, redirectMatch{path: "/a/", to: "/b/$1"}, false},
+		{"other_capture", redirect{From: "^/(.*)/x/(.*)$", To: "/$2/$1"}, redirectMatch{to: "/$2/$1", prefix: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.r.match()
+			if ok != tt.wantOk {
+				t.Fatalf("redirect.match() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redirect.match() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_writeRedirects(t *testing.T) {
+	redirects := filepath.Join(t.TempDir(), "redirects.json")
+	err := ioutil.WriteFile(redirects, []byte(`[
+		{"from": "^/old-post/$", "to": "__GHOST_URL__/hello/", "permanent": true},
+		{"from": "^/blog/(.*)$", "to": "/$1"},
+		{"from": "^/(a|b)/$", "to": "https://example.com/"}
+	]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Converter{
+		path:      t.TempDir(),
+		kind:      metadecoders.TOML,
+		baseURL:   "https://blog.example.com/",
+		redirects: redirects,
+		info: info{Data: data{Posts: []postRef{
+			{Slug: "hello", Page: json.RawMessage("false")},
+		}}},
+	}
+	c.indexPosts()
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.writeRedirects(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string][]string{"hello": {"/old-post/"}}; !reflect.DeepEqual(c.redirectAliases, want) {
+		t.Errorf("Converter.writeRedirects() aliases = %v, want %v", c.redirectAliases, want)
+	}
+
+	for name, wants := range map[string][]string{
+		"static/_redirects": {
+			"/old-post/ /post/hello/ 301\n",
+			"/blog/* /:splat 302\n",
+		},
+		"vercel.json": {
+			`"source": "/old-post/"`,
+			`"destination": "/post/hello/"`,
+			`"source": "/blog/:path*"`,
+			`"destination": "/:path*"`,
+		},
+		"cloudflare-redirects.json": {
+			`"source_url": "blog.example.com/old-post/"`,
+			`"target_url": "https://blog.example.com/post/hello/"`,
+			`"source_url": "blog.example.com/blog/"`,
+			`"preserve_path_suffix": true`,
+		},
+		"nginx-redirects.conf": {
+			`"/old-post/" "/post/hello/";`,
+			`"~^/blog/(.*)$" "/$1";`,
+			`"~^/(a|b)/$" "https://example.com/";`,
+		},
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(c.path, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("Converter.writeRedirects() %s = %s, want %s", name, data, want)
+			}
+		}
+		if name != "nginx-redirects.conf" && strings.Contains(string(data), "a|b") {
+			t.Errorf("Converter.writeRedirects() %s = %s, want no regex redirect", name, data)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package ghosttohugo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jww "github.com/spf13/jwalterweatherman"
+	"gopkg.in/yaml.v2"
+)
+
+// routes holds the parts of a Ghost routes.yaml file that map onto a Hugo
+// site
+type routes struct {
+	collections []collection
+	channels    []channel
+	taxonomies  map[string]string // Ghost taxonomy to URL template
+}
+
+// collection is a Ghost collection. Each post belongs to the first
+// collection whose filter it matches, and becomes part of its section.
+type collection struct {
+	path      string
+	permalink string
+	filter    string
+	section   string
+}
+
+// channel is a Ghost channel route, listing the posts matching its filter
+type channel struct {
+	path     string
+	filter   string
+	template string
+}
+
+// routeOptions is the value of a route or collection in routes.yaml
+type routeOptions struct {
+	Permalink  string
+	Filter     string
+	Controller string
+	Template   interface{}
+}
+
+// template returns the first template of the route, which Ghost accepts as
+// a name or a list of names.
+func (o routeOptions) template() string {
+	switch t := o.Template.(type) {
+	case string:
+		return t
+	case []interface{}:
+		if len(t) > 0 {
+			return fmt.Sprint(t[0])
+		}
+	}
+	return ""
+}
+
+// readRoutes reads a Ghost routes.yaml file. Collections and routes are
+// decoded in order, as posts belong to the first collection they match.
+func readRoutes(path string) (*routes, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Routes      yaml.MapSlice     `yaml:"routes"`
+		Collections yaml.MapSlice     `yaml:"collections"`
+		Taxonomies  map[string]string `yaml:"taxonomies"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	r := &routes{taxonomies: doc.Taxonomies}
+	for _, item := range doc.Collections {
+		o := decodeRoute(item.Value)
+		path := fmt.Sprint(item.Key)
+		r.collections = append(r.collections, collection{
+			path:      path,
+			permalink: o.Permalink,
+			filter:    o.Filter,
+			section:   routeSection(path),
+		})
+	}
+	for _, item := range doc.Routes {
+		o := decodeRoute(item.Value)
+		path := fmt.Sprint(item.Key)
+		if o.Controller != "channel" {
+			jww.INFO.Printf("route %s is not converted\n", path)
+			continue
+		}
+		r.channels = append(r.channels, channel{
+			path:     path,
+			filter:   o.Filter,
+			template: o.template(),
+		})
+	}
+
+	return r, nil
+}
+
+// decodeRoute decodes the value of a route, which is either the name of a
+// template or a map of options.
+func decodeRoute(value interface{}) routeOptions {
+	var o routeOptions
+	switch v := value.(type) {
+	case string:
+		o.Template = v
+	case yaml.MapSlice:
+		for _, item := range v {
+			switch strings.ToLower(fmt.Sprint(item.Key)) {
+			case "permalink":
+				o.Permalink = fmt.Sprint(item.Value)
+			case "filter":
+				o.Filter = fmt.Sprint(item.Value)
+			case "controller":
+				o.Controller = fmt.Sprint(item.Value)
+			case "template":
+				o.Template = item.Value
+			}
+		}
+	}
+	return o
+}
+
+// routeSection returns the Hugo section of the posts of a collection or
+// channel at path. Posts of the root collection stay in the post section.
+func routeSection(path string) string {
+	section := strings.Replace(strings.Trim(path, "/"), "/", "-", -1)
+	if section == "" {
+		return "post"
+	}
+	return section
+}
+
+// collection returns the collection a post belongs to, if any
+func (c Converter) collection(p post) (collection, bool) {
+	if c.routes == nil || p.isPage() {
+		return collection{}, false
+	}
+	for _, col := range c.routes.collections {
+		if col.filter == "" || matchFilter(col.filter, p) {
+			return col, true
+		}
+	}
+	return collection{}, false
+}
+
+// hugoPermalink translates a Ghost URL template into a Hugo permalink. It
+// reports false when the template uses data Hugo permalinks can not express.
+func hugoPermalink(template string) (string, bool) {
+	permalink := strings.NewReplacer(
+		"{slug}", ":slug",
+		"{year}", ":year",
+		"{month}", ":month",
+		"{day}", ":day",
+	).Replace(template)
+	return permalink, !strings.Contains(permalink, "{")
+}
+
+// permalinks returns the permalinks config reproducing the URLs of the Ghost
+// collections and taxonomies.
+func (c Converter) permalinks() map[string]interface{} {
+	permalinks := make(map[string]interface{})
+	if c.routes == nil {
+		return permalinks
+	}
+
+	for _, col := range c.routes.collections {
+		if _, ok := permalinks[col.section]; ok || col.permalink == "" {
+			continue
+		}
+		permalink, ok := hugoPermalink(col.permalink)
+		if !ok {
+			jww.INFO.Printf(
+				"collection %s permalink %s is set on each post\n",
+				col.path, col.permalink,
+			)
+			continue
+		}
+		permalinks[col.section] = permalink
+	}
+	for taxonomy, plural := range map[string]string{
+		"tag":    "tags",
+		"author": "authors",
+	} {
+		if template := c.routes.taxonomies[taxonomy]; template != "" {
+			if permalink, ok := hugoPermalink(template); ok {
+				permalinks[plural] = permalink
+			}
+		}
+	}
+
+	return permalinks
+}
+
+// termURL returns the path of a tag or author term page in the Hugo site
+func (c Converter) termURL(taxonomy, slug string) string {
+	if c.routes != nil {
+		if template := c.routes.taxonomies[taxonomy]; template != "" {
+			if permalink, ok := hugoPermalink(template); ok {
+				return strings.Replace(permalink, ":slug", slug, -1)
+			}
+		}
+	}
+	return "/" + taxonomy + "s/" + slug + "/"
+}
+
+// hasTaxonomyRoute reports whether routes.yaml sets the URL of the taxonomy,
+// in which case the term pages keep their Ghost URL.
+func (c Converter) hasTaxonomyRoute(taxonomy string) bool {
+	if c.routes == nil {
+		return false
+	}
+	template := c.routes.taxonomies[taxonomy]
+	_, ok := hugoPermalink(template)
+	return template != "" && ok
+}
+
+// writeChannels writes a section page for each Ghost channel, carrying its
+// filter and template so a layout can list the matching posts.
+func (c *Converter) writeChannels() error {
+	if c.routes == nil {
+		return nil
+	}
+
+	for _, ch := range c.routes.channels {
+		section := routeSection(ch.path)
+		metadata := map[string]interface{}{
+			"title": strings.Trim(ch.path, "/"),
+			"url":   ch.path,
+		}
+		if ch.filter != "" {
+			metadata["ghost_filter"] = ch.filter
+		}
+		if ch.template != "" {
+			metadata["layout"] = ch.template
+		}
+
+		path := filepath.Join(c.path, "content", section, "_index.md")
+		if err := c.writeContent(path, section, metadata, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchFilter reports whether the post matches a Ghost filter. Filters are
+// made of key:value expressions, joined by + (and) or , (or), where the value
+// can be negated with - or be a [list]. The tag, primary_tag, author,
+// primary_author and featured keys are supported.
+func matchFilter(filter string, p post) bool {
+	for _, all := range splitFilter(filter, '+') {
+		matched := false
+		for _, expr := range splitFilter(all, ',') {
+			if matchExpr(strings.TrimSpace(expr), p) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// splitFilter splits a filter on sep, outside of [lists]
+func splitFilter(filter string, sep rune) []string {
+	var (
+		parts []string
+		depth int
+		last  int
+	)
+	for i, r := range filter {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, filter[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(parts, filter[last:])
+}
+
+func matchExpr(expr string, p post) bool {
+	parts := strings.SplitN(expr, ":", 2)
+	if len(parts) != 2 {
+		jww.WARN.Printf("unsupported filter expression %q\n", expr)
+		return false
+	}
+	key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	negate := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+	values := []string{strings.Trim(value, `'"`)}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		values = values[:0]
+		for _, v := range strings.Split(value[1:len(value)-1], ",") {
+			values = append(values, strings.Trim(strings.TrimSpace(v), `'"`))
+		}
+	}
+
+	var have []string
+	switch key {
+	case "tag", "tags", "tags.slug":
+		have = append(append(have, p.Tags...), p.InternalSlugs...)
+	case "primary_tag", "primary_tag.slug":
+		if len(p.Tags) > 0 {
+			have = p.Tags[:1]
+		}
+	case "author", "authors", "authors.slug":
+		have = p.Authors
+	case "primary_author", "primary_author.slug":
+		if len(p.Authors) > 0 {
+			have = p.Authors[:1]
+		}
+	case "featured":
+		have = []string{fmt.Sprint(parseBool(p.Featured))}
+	default:
+		jww.WARN.Printf("unsupported filter key %q\n", key)
+		return false
+	}
+
+	found := false
+	for _, v := range values {
+		for _, h := range have {
+			found = found || strings.EqualFold(v, h)
+		}
+	}
+	return found != negate
+}
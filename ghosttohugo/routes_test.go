@@ -0,0 +1,226 @@
+package ghosttohugo
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+)
+
+const testRoutes = `
+routes:
+  /about-team/: team
+  /podcast-channel/:
+    controller: channel
+    filter: tag:podcast
+    template:
+      - podcast
+      - index
+
+collections:
+  /blog/:
+    permalink: /blog/{slug}/
+    filter: primary_tag:blog
+  /podcast/:
+    permalink: /podcast/{primary_author}/{slug}/
+    filter: tag:podcast
+  /:
+    permalink: /{year}/{slug}/
+
+taxonomies:
+  tag: /topic/{slug}/
+  author: /author/{slug}/
+`
+
+func writeTestRoutes(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := ioutil.WriteFile(path, []byte(testRoutes), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_readRoutes(t *testing.T) {
+	got, err := readRoutes(writeTestRoutes(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &routes{
+		collections: []collection{
+			{path: "/blog/", permalink: "/blog/{slug}/", filter: "primary_tag:blog", section: "blog"},
+			{path: "/podcast/", permalink: "/podcast/{primary_author}/{slug}/", filter: "tag:podcast", section: "podcast"},
+			{path: "/", permalink: "/{year}/{slug}/", section: "post"},
+		},
+		channels: []channel{
+			{path: "/podcast-channel/", filter: "tag:podcast", template: "podcast"},
+		},
+		taxonomies: map[string]string{
+			"tag":    "/topic/{slug}/",
+			"author": "/author/{slug}/",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readRoutes() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_readRoutes_flow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	data := `collections: {"/z/": {permalink: "/z/{slug}/"}, "/": {permalink: "/{slug}/"}, "/a/": {filter: "tag:a"}}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readRoutes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []collection{
+		{path: "/z/", permalink: "/z/{slug}/", section: "z"},
+		{path: "/", permalink: "/{slug}/", section: "post"},
+		{path: "/a/", filter: "tag:a", section: "a"},
+	}
+	if !reflect.DeepEqual(got.collections, want) {
+		t.Errorf("readRoutes() collections = %+v, want %+v", got.collections, want)
+	}
+}
+
+func Test_matchFilter(t *testing.T) {
+	p := post{
+		Tags:          []string{"blog", "podcast"},
+		Authors:       []string{"jo", "sam"},
+		Featured:      json.RawMessage("true"),
+		InternalSlugs: []string{"hash-members"},
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{"tag", "tag:podcast", true},
+		{"missing_tag", "tag:news", false},
+		{"primary_tag", "primary_tag:blog", true},
+		{"not_primary_tag", "primary_tag:podcast", false},
+		{"negated", "tag:-news", true},
+		{"negated_match", "tag:-blog", false},
+		{"list", "tag:[news, podcast]", true},
+		{"or", "tag:news,author:sam", true},
+		{"and", "tag:blog+primary_author:sam", false},
+		{"and_list", "tag:[news,blog]+featured:true", true},
+		{"internal_tag", "tag:hash-members", true},
+		{"internal_not_primary", "primary_tag:hash-members", false},
+		{"unsupported", "visibility:paid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchFilter(tt.filter, p); got != tt.want {
+				t.Errorf("matchFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_routes(t *testing.T) {
+	r, err := readRoutes(writeTestRoutes(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Converter{routes: r}
+	date := time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		p       post
+		section string
+		hugo    string
+	}{
+		{"blog", post{Slug: "a", Tags: []string{"blog", "podcast"}}, "blog", "/blog/a/"},
+		{"podcast", post{Slug: "b", Tags: []string{"news", "podcast"}, Authors: []string{"jo"}}, "podcast", "/podcast/jo/b/"},
+		{"root", post{Slug: "c", Published: date}, "post", "/2020/c/"},
+		{"page", post{Slug: "d", Page: json.RawMessage("true"), Tags: []string{"blog"}}, "", "/d/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col, _ := c.collection(tt.p)
+			if col.section != tt.section {
+				t.Errorf("Converter.collection() = %v, want %v", col.section, tt.section)
+			}
+			if got := c.hugoURL(tt.p); got != tt.hugo {
+				t.Errorf("Converter.hugoURL() = %v, want %v", got, tt.hugo)
+			}
+			if got := c.ghostPath(tt.p); got != tt.hugo {
+				t.Errorf("Converter.ghostPath() = %v, want %v", got, tt.hugo)
+			}
+		})
+	}
+
+	want := map[string]interface{}{
+		"blog":    "/blog/:slug/",
+		"post":    "/:year/:slug/",
+		"tags":    "/topic/:slug/",
+		"authors": "/author/:slug/",
+	}
+	if got := c.permalinks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Converter.permalinks() = %v, want %v", got, want)
+	}
+	if got := c.termURL("tag", "news"); got != "/topic/news/" {
+		t.Errorf("Converter.termURL() = %v, want /topic/news/", got)
+	}
+	if got := (Converter{}).termURL("tag", "news"); got != "/tags/news/" {
+		t.Errorf("Converter.termURL() = %v, want /tags/news/", got)
+	}
+}
+
+func TestConverter_writePost_routes(t *testing.T) {
+	r, err := readRoutes(writeTestRoutes(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Converter{path: t.TempDir(), kind: metadecoders.TOML, routes: r}
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.writeChannels(); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := []post{
+		{Slug: "a", Status: "published", Tags: []string{"blog"}},
+		{Slug: "b", Status: "published", Tags: []string{"podcast"}, Authors: []string{"jo"}},
+	}
+	for _, p := range posts {
+		if err := c.writePost(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, wants := range map[string][]string{
+		"blog/a.md":                 {`slug = "a"`},
+		"podcast/b.md":              {`url = "/podcast/jo/b/"`},
+		"podcast-channel/_index.md": {`ghost_filter = "tag:podcast"`, `layout = "podcast"`, `url = "/podcast-channel/"`},
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(c.path, "content", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s = %s, want %s", name, data, want)
+			}
+		}
+		if strings.Contains(string(data), "aliases") {
+			t.Errorf("%s = %s, want no aliases", name, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(c.path, "content", "about-team")); !os.IsNotExist(err) {
+		t.Errorf("template route was converted: %v", err)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugolib"
 	"github.com/gohugoio/hugo/parser"
+	jww "github.com/spf13/jwalterweatherman"
 )
 
 func (c *Converter) createSite() error {
@@ -60,6 +63,55 @@ func (c *Converter) createSite() error {
 		galleryImgData,
 		0644,
 	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/callout.html"),
+		calloutData,
+		0644,
+	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/toggle.html"),
+		toggleData,
+		0644,
+	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/button.html"),
+		buttonData,
+		0644,
+	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/header.html"),
+		headerData,
+		0644,
+	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/audio.html"),
+		audioData,
+		0644,
+	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/video.html"),
+		videoData,
+		0644,
+	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/file.html"),
+		fileData,
+		0644,
+	)
+	ioutil.WriteFile(
+		filepath.Join(c.path, "layouts/shortcodes/members.html"),
+		membersData,
+		0644,
+	)
+
+	if c.head {
+		mkdir(c.path, filepath.Clean("layouts/partials"))
+		ioutil.WriteFile(
+			filepath.Join(c.path, "layouts/partials/ghost_head.html"),
+			c.headData(),
+			0644,
+		)
+	}
 
 	c.site = s
 
@@ -68,22 +120,118 @@ func (c *Converter) createSite() error {
 	return nil
 }
 
-func (c Converter) createConfig() error {
+// writeContent writes a content file to path. Links in the content and the
+// front matter are rewritten, and the files they reference are copied into
+// the page bundle or the static folder. name identifies the page in logs.
+func (c *Converter) writeContent(
+	path, name string,
+	metadata map[string]interface{},
+	content string,
+) error {
+	content = c.rewriteURLs(content)
+	c.rewriteFrontMatter(metadata)
+	c.reportOffsite(name, append(frontMatterStrings(metadata), content)...)
+
+	base := filepath.Base(path)
+	bundle := c.bundle && (base == "index.md" || base == "_index.md")
+	if bundle {
+		content = c.bundleImages(filepath.Dir(path), metadata, content)
+	}
+	c.copyAssets(append(frontMatterStrings(metadata), content)...)
+	if c.fetcher != nil {
+		dir := filepath.Join(c.path, "static", "images", "remote")
+		prefix := "/images/remote/"
+		if bundle {
+			dir, prefix = filepath.Dir(path), ""
+		}
+		content = c.fetchImages(dir, prefix, metadata, content)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := parser.InterfaceToFrontMatter(metadata, c.kind, buf); err != nil {
+		return err
+	}
+	if _, err := buf.Write([]byte("\n\n")); err != nil {
+		return err
+	}
+	if _, err := buf.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return helpers.WriteToDisk(path, bytes.NewReader(buf.Bytes()), c.site.Fs.Source)
+}
+
+// siteBaseURL returns the URL the Hugo site will be served from
+func (c Converter) siteBaseURL() string {
+	if c.baseURL != "" {
+		return c.baseURL
+	}
+	return "http://example.org/"
+}
+
+func (c *Converter) createConfig() error {
 	title := "My New Hugo Site"
-	baseURL := "http://example.org/"
+	baseURL := c.siteBaseURL()
+	languageCode := "en-us"
+	params := make(map[string]interface{})
+	social := make(map[string]interface{})
+	var paginate int
+
+	for _, key := range []string{"locale", "lang"} {
+		if value := c.info.settings[key]; value != "" {
+			languageCode = strings.Replace(strings.ToLower(value), "_", "-", -1)
+			break
+		}
+	}
 
 	for key, value := range c.info.settings {
-		switch strings.ToLower(key) {
+		if value == "" {
+			continue
+		}
+		switch key = strings.ToLower(key); key {
 		case "title":
 			title = value
+		case "posts_per_page":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				jww.WARN.Printf("ignoring posts_per_page setting %q\n", value)
+				continue
+			}
+			paginate = n
+		case "facebook":
+			social[key] = value
+		case "twitter":
+			social[key] = strings.TrimPrefix(value, "@")
+		case "description", "logo", "icon", "cover_image", "accent_color",
+			"meta_title", "meta_description", "og_title", "og_description",
+			"og_image", "twitter_title", "twitter_description",
+			"twitter_image":
+			params[key] = value
 		}
 	}
 
+	c.rewriteFrontMatter(params)
+	c.copyAssets(frontMatterStrings(params)...)
+
+	taxonomies := map[string]interface{}{
+		"tag":    "tags",
+		"author": "authors",
+	}
+	if c.categories != CategoriesNone {
+		taxonomies["category"] = "categories"
+	}
+
 	in := map[string]interface{}{
 		"baseURL":            baseURL,
 		"title":              title,
-		"languageCode":       "en-us",
+		"languageCode":       languageCode,
 		"disablePathToLower": true,
+		"taxonomies":         taxonomies,
+		"frontmatter": map[string]interface{}{
+			"date":        []string{"date", "publishDate", "lastmod"},
+			"publishDate": []string{"publishDate", "date"},
+			"lastmod":     []string{"lastmod", "date", "publishDate"},
+		},
 		"markup": map[string]interface{}{
 			"goldmark": map[string]interface{}{
 				"renderer": map[string]interface{}{"unsafe": true},
@@ -91,6 +239,25 @@ func (c Converter) createConfig() error {
 		},
 	}
 
+	if c.location != nil && c.location != time.Local {
+		in["timeZone"] = c.location.String()
+	}
+	if len(params) > 0 {
+		in["params"] = params
+	}
+	if len(social) > 0 {
+		in["social"] = social
+	}
+	if paginate > 0 {
+		in["paginate"] = paginate
+	}
+	if permalinks := c.permalinks(); len(permalinks) > 0 {
+		in["permalinks"] = permalinks
+	}
+	if menus := c.menus(); len(menus) > 0 {
+		in["menu"] = menus
+	}
+
 	var buf bytes.Buffer
 	if err := parser.InterfaceToConfig(in, c.kind, &buf); err != nil {
 		return err
@@ -103,6 +270,29 @@ func (c Converter) createConfig() error {
 	)
 }
 
+// headData is the ghost_head.html partial. It links the canonical URL of the
+// page, and passes the Ghost uuid on to Disqus when the page has one.
+func (c Converter) headData() []byte {
+	head := `{{/* Include in the <head> of the theme: {{ partial "ghost_head.html" . }} */}}
+{{ with .Params.canonical }}
+<link rel="canonical" href="{{ . }}">
+{{ else }}
+<link rel="canonical" href="{{ .Permalink }}">
+{{ end }}`
+	if c.identifier != "" {
+		head += fmt.Sprintf(`
+{{ with .Param %q }}
+<script>
+  var disqus_config = function () {
+    this.page.identifier = {{ . }};
+    this.page.url = {{ $.Permalink }};
+  };
+</script>
+{{ end }}`, strings.ToLower(c.identifier))
+	}
+	return []byte(head)
+}
+
 var bookmarkData = []byte(`<figure class="kg-card kg-bookmark-card">
   <a href="{{ .Get "url" }}" class="kg-bookmark-container">
     <div class="kg-bookmark-content">
@@ -132,15 +322,84 @@ var galleryData = []byte(`<figure class="kg-gallery-card kg-width-wide">
     </div>
   </div>
   {{ with .Get "caption" }}
-  <figcaption>{{ . }}</figcaption>
+  <figcaption>{{ . | markdownify }}</figcaption>
   {{ end }}
 </figure>`)
 
 var galleryImgData = []byte(`
   <div class="kg-gallery-image">
-    <img src="{{ .Get "src" }}" width="{{ .Get "width" }}" height="{{ .Get "height" }}">
+    {{ with .Get "link" }}<a href="{{ . }}">{{ end }}
+    <img src="{{ .Get "src" }}"
+      {{- with .Get "width" }} width="{{ . }}"{{ end }}
+      {{- with .Get "height" }} height="{{ . }}"{{ end }}
+      {{- with .Get "alt" }} alt="{{ . }}"{{ end }}
+      {{- with .Get "title" }} title="{{ . }}"{{ end }}>
+    {{ if .Get "link" }}</a>{{ end }}
   </div>
 {{ if mod .Ordinal 3 | eq 2 }}
 </div>
 <div class="kg-gallery-row">
 {{ end }}`)
+
+var calloutData = []byte(`<div class="kg-card kg-callout-card kg-callout-card-{{ .Get "color" }}">
+  {{ with .Get "emoji" }}<div class="kg-callout-emoji">{{ . }}</div>{{ end }}
+  <div class="kg-callout-text">{{ .Inner | markdownify }}</div>
+</div>`)
+
+var toggleData = []byte(`<details class="kg-card kg-toggle-card">
+  <summary class="kg-toggle-heading">{{ .Get "heading" | markdownify }}</summary>
+  <div class="kg-toggle-content">{{ .Inner | markdownify }}</div>
+</details>`)
+
+var buttonData = []byte(`<div class="kg-card kg-button-card kg-align-{{ .Get "alignment" | default "left" }}">
+  <a href="{{ .Get "url" }}" class="kg-btn kg-btn-accent">{{ .Get "text" }}</a>
+</div>`)
+
+var headerData = []byte(`<div class="kg-card kg-header-card kg-size-{{ .Get "size" }} kg-style-{{ .Get "style" }}"
+  {{- with .Get "image" }} style="background-image: url({{ . }})"{{ end }}>
+  {{ with .Get "header" }}<h2 class="kg-header-card-header">{{ . | markdownify }}</h2>{{ end }}
+  {{ with .Get "subheader" }}<h3 class="kg-header-card-subheader">{{ . | markdownify }}</h3>{{ end }}
+  {{ with .Get "buttonUrl" }}
+  <a href="{{ . }}" class="kg-header-card-button">{{ $.Get "buttonText" }}</a>
+  {{ end }}
+</div>`)
+
+var audioData = []byte(`<div class="kg-card kg-audio-card">
+  {{ with .Get "thumbnail" }}<img src="{{ . }}" alt="audio-thumbnail" class="kg-audio-thumbnail">{{ end }}
+  <div class="kg-audio-player-container">
+    <div class="kg-audio-title">{{ .Get "title" }}</div>
+    <audio src="{{ .Get "src" }}" preload="metadata" controls></audio>
+    {{ with .Get "duration" }}<span class="kg-audio-duration">{{ . }}</span>{{ end }}
+  </div>
+</div>`)
+
+var videoData = []byte(`<figure class="kg-card kg-video-card">
+  <video src="{{ .Get "src" }}"
+    {{- with .Get "thumbnail" }} poster="{{ . }}"{{ end }}
+    {{- with .Get "width" }} width="{{ . }}"{{ end }}
+    {{- with .Get "height" }} height="{{ . }}"{{ end }}
+    {{- if eq (.Get "loop") "true" }} loop autoplay muted playsinline{{ end }}
+    preload="metadata" controls></video>
+  {{ with .Get "caption" }}
+  <figcaption>{{ . | markdownify }}</figcaption>
+  {{ end }}
+</figure>`)
+
+var fileData = []byte(`<div class="kg-card kg-file-card">
+  <a class="kg-file-card-container" href="{{ .Get "src" }}" download>
+    <div class="kg-file-card-contents">
+      <div class="kg-file-card-title">{{ .Get "title" | default (.Get "name") }}</div>
+      {{ with .Get "caption" }}<div class="kg-file-card-caption">{{ . }}</div>{{ end }}
+      <div class="kg-file-card-metadata">
+        <div class="kg-file-card-filename">{{ .Get "name" }}</div>
+        {{ with .Get "size" }}<div class="kg-file-card-filesize">{{ . }}</div>{{ end }}
+      </div>
+    </div>
+  </a>
+</div>`)
+
+var membersData = []byte(`<div class="members-only" data-visibility="{{ .Page.Params.visibility }}">
+
+{{ .Inner }}
+
+</div>`)
@@ -0,0 +1,188 @@
+package ghosttohugo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+)
+
+func TestConverter_createConfig(t *testing.T) {
+	export := t.TempDir()
+	p := filepath.Join(export, "content", "images", "logo.png")
+	if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(p, []byte("logo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	location, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Converter{
+		path:       t.TempDir(),
+		location:   location,
+		kind:       metadecoders.TOML,
+		baseURL:    "https://blog.example.com/",
+		categories: CategoriesNone,
+		assets:     &assets{fs: dirFS(export), copied: make(map[string]bool)},
+		info: info{settings: map[string]string{
+			"title":          "My Blog",
+			"description":    "Thoughts",
+			"logo":           "__GHOST_URL__/content/images/logo.png",
+			"icon":           "",
+			"accent_color":   "#15171A",
+			"facebook":       "ghost",
+			"twitter":        "@ghost",
+			"locale":         "en_GB",
+			"posts_per_page": "5",
+			"og_title":       "OG title",
+			"active_theme":   "casper",
+		}},
+	}
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(c.path, "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := metadecoders.Default.UnmarshalToMap(data, metadecoders.TOML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"baseURL":            "https://blog.example.com/",
+		"title":              "My Blog",
+		"languageCode":       "en-gb",
+		"disablePathToLower": true,
+		"paginate":           int64(5),
+		"timeZone":           "Europe/London",
+		"taxonomies": map[string]interface{}{
+			"tag":    "tags",
+			"author": "authors",
+		},
+		"params": map[string]interface{}{
+			"description":  "Thoughts",
+			"logo":         "/images/logo.png",
+			"accent_color": "#15171A",
+			"og_title":     "OG title",
+		},
+		"social": map[string]interface{}{
+			"facebook": "ghost",
+			"twitter":  "ghost",
+		},
+		"frontmatter": map[string]interface{}{
+			"date":        []interface{}{"date", "publishDate", "lastmod"},
+			"publishDate": []interface{}{"publishDate", "date"},
+			"lastmod":     []interface{}{"lastmod", "date", "publishDate"},
+		},
+		"markup": map[string]interface{}{
+			"goldmark": map[string]interface{}{
+				"renderer": map[string]interface{}{"unsafe": true},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Converter.createConfig() = %v, want %v", got, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(c.path, "static", "images", "logo.png")); err != nil {
+		t.Errorf("Converter.createConfig() did not copy the logo: %v", err)
+	}
+}
+
+func TestConverter_createConfig_languageCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     interface{}
+	}{
+		{"locale", map[string]string{"locale": "en_GB"}, "en-gb"},
+		{"lang", map[string]string{"lang": "fr"}, "fr"},
+		{"both", map[string]string{"lang": "fr", "locale": "en_GB"}, "en-gb"},
+		{"default", map[string]string{}, "en-us"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Converter{
+				path: t.TempDir(),
+				kind: metadecoders.TOML,
+				info: info{settings: tt.settings},
+			}
+			if err := c.createSite(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := ioutil.ReadFile(filepath.Join(c.path, "config.toml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := metadecoders.Default.UnmarshalToMap(data, metadecoders.TOML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got["languageCode"] != tt.want {
+				t.Errorf("Converter.createConfig() languageCode = %v, want %v", got["languageCode"], tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_headData(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+	}{
+		{"canonical_only", "", ""},
+		{"disqus", "disqus_identifier", `.Param "disqus_identifier"`},
+		{"nested", "params.Comment_ID", `.Param "params.comment_id"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{identifier: tt.identifier}
+			head := string(c.headData())
+			if _, err := template.New("head").Parse(head); err != nil {
+				t.Fatalf("Converter.headData() does not parse: %v", err)
+			}
+			if !strings.Contains(head, `rel="canonical"`) {
+				t.Errorf("Converter.headData() = %q, want a canonical link", head)
+			}
+			if tt.want == "" && strings.Contains(head, "disqus_config") {
+				t.Errorf("Converter.headData() = %q, want no identifier", head)
+			}
+			if !strings.Contains(head, tt.want) {
+				t.Errorf("Converter.headData() = %q, want %q", head, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_createSite_shortcodes(t *testing.T) {
+	c := &Converter{path: t.TempDir(), kind: metadecoders.TOML}
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		"bookmark", "gallery", "galleryImg", "callout", "toggle", "button", "header",
+		"audio", "video", "file", "members",
+	} {
+		path := filepath.Join(c.path, "layouts", "shortcodes", name+".html")
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Converter.createSite() did not write %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package ghosttohugo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// term returns the tags taxonomy term for the tag. This is the Ghost slug,
+// so the term page keeps the URL it had in Ghost even when the display name
+// differs.
+func (t tag) term() string {
+	if t.Slug != "" {
+		return t.Slug
+	}
+	return strings.TrimPrefix(t.Name, "#")
+}
+
+// isInternal reports whether the tag is a Ghost internal tag. These start
+// with a # and are never shown on the site.
+func (t tag) isInternal() bool {
+	return t.Visibility == "internal" || strings.HasPrefix(t.Name, "#")
+}
+
+// internalName returns the name of an internal tag without the leading #
+func (t tag) internalName() string {
+	return strings.TrimPrefix(t.Name, "#")
+}
+
+func (t tag) frontMatter() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"title": t.Name,
+	}
+
+	for key, value := range map[string]string{
+		"slug":             t.Slug,
+		"description":      t.Description,
+		"meta_title":       t.MetaTitle,
+		"meta_description": t.MetaDescription,
+		"accent_color":     t.AccentColor,
+		"image":            t.FeatureImage,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	if _, ok := metadata["image"]; !ok && t.Image != "" {
+		metadata["image"] = t.Image
+	}
+
+	return metadata
+}
+
+// isCategory reports whether the tag is used as a category of the posts it
+// is assigned to, given whether it is the primary tag of the post.
+func (c Converter) isCategory(t tag, primary bool) bool {
+	switch c.categories {
+	case CategoriesPrimary:
+		return primary
+	case CategoriesPrefix:
+		for _, prefix := range c.catValues {
+			if strings.HasPrefix(t.Name, prefix) || strings.HasPrefix(t.Slug, prefix) {
+				return true
+			}
+		}
+	case CategoriesList:
+		for _, name := range c.catValues {
+			if strings.EqualFold(t.Name, name) || strings.EqualFold(t.Slug, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// internalRule maps an internal tag to a behavior of the posts carrying it
+type internalRule struct {
+	tag, behavior, value string
+}
+
+func (r internalRule) validate() error {
+	switch r.behavior {
+	case InternalHide:
+	case InternalLayout, InternalFlag:
+		if r.value == "" {
+			return fmt.Errorf("internal tag rule %s needs a value for %s",
+				r.tag, r.behavior)
+		}
+	default:
+		return fmt.Errorf("unknown internal tag behavior %q", r.behavior)
+	}
+	return nil
+}
+
+// applyInternalTags lists the internal tags of a post in its front matter,
+// and applies the behaviors the rules attach to them.
+func (c Converter) applyInternalTags(
+	metadata map[string]interface{},
+	tags []string,
+) {
+	if len(tags) == 0 {
+		return
+	}
+	if c.internal != "" {
+		setKey(metadata, c.internal, tags)
+	}
+
+	for _, rule := range c.rules {
+		for _, name := range tags {
+			if !strings.EqualFold(name, rule.tag) {
+				continue
+			}
+			switch rule.behavior {
+			case InternalHide:
+				setKey(metadata, "_build.list", "never")
+			case InternalLayout:
+				metadata["layout"] = rule.value
+			case InternalFlag:
+				setKey(metadata, rule.value, true)
+			}
+		}
+	}
+}
+
+// writeTags writes a term page for each public Ghost tag to the tags taxonomy,
+// carrying its description, image and SEO fields, and an alias for its Ghost
+// archive URL.
+func (c *Converter) writeTags() error {
+	for _, t := range c.info.Data.Tags {
+		if t.Slug == "" || t.isInternal() {
+			continue
+		}
+
+		metadata := t.frontMatter()
+		if !c.hasTaxonomyRoute("tag") {
+			metadata["aliases"] = []string{"/tag/" + t.Slug + "/"}
+		}
+
+		path := filepath.Join(c.path, "content", "tags", t.Slug, "_index.md")
+		if err := c.writeContent(path, t.Slug, metadata, t.Description); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
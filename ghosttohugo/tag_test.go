@@ -0,0 +1,149 @@
+package ghosttohugo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tag_term(t *testing.T) {
+	tests := []struct {
+		name string
+		t    tag
+		want string
+	}{
+		{"slug", tag{Name: "Getting Started", Slug: "getting-started"}, "getting-started"},
+		{"name", tag{Name: "Getting Started"}, "Getting Started"},
+		{"internal_name", tag{Name: "#internal"}, "internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.term(); got != tt.want {
+				t.Errorf("tag.term() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tag_frontMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		t    tag
+		want map[string]interface{}
+	}{
+		{"name_only", tag{Name: "News"}, map[string]interface{}{"title": "News"}},
+		{
+			"full",
+			tag{
+				Name:            "Getting Started",
+				Slug:            "start",
+				Description:     "First steps",
+				FeatureImage:    "/content/images/start.jpg",
+				Image:           "/content/images/old.jpg",
+				MetaTitle:       "Start here",
+				MetaDescription: "How to start",
+				AccentColor:     "#ff0000",
+			},
+			map[string]interface{}{
+				"title":            "Getting Started",
+				"slug":             "start",
+				"description":      "First steps",
+				"image":            "/content/images/start.jpg",
+				"meta_title":       "Start here",
+				"meta_description": "How to start",
+				"accent_color":     "#ff0000",
+			},
+		},
+		{
+			"slug",
+			tag{Name: "C#", Slug: "c-sharp"},
+			map[string]interface{}{"title": "C#", "slug": "c-sharp"},
+		},
+		{
+			"legacy_image",
+			tag{Name: "News", Image: "/content/images/old.jpg"},
+			map[string]interface{}{
+				"title": "News",
+				"image": "/content/images/old.jpg",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.frontMatter(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tag.frontMatter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tag_isInternal(t *testing.T) {
+	tests := []struct {
+		name string
+		t    tag
+		want bool
+	}{
+		{"public", tag{Name: "News", Slug: "news"}, false},
+		{"hash", tag{Name: "#hide-from-feed", Slug: "hash-hide-from-feed"}, true},
+		{"visibility", tag{Name: "Hidden", Visibility: "internal"}, true},
+		{"public_visibility", tag{Name: "News", Visibility: "public"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.isInternal(); got != tt.want {
+				t.Errorf("tag.isInternal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_applyInternalTags(t *testing.T) {
+	rules := []internalRule{
+		{tag: "hide-from-feed", behavior: InternalHide},
+		{tag: "newsletter-only", behavior: InternalLayout, value: "newsletter"},
+		{tag: "Newsletter-Only", behavior: InternalFlag, value: "params.newsletter"},
+	}
+
+	tests := []struct {
+		name     string
+		internal string
+		tags     []string
+		want     map[string]interface{}
+	}{
+		{"none", "internalTags", nil, map[string]interface{}{}},
+		{
+			"key",
+			"internalTags",
+			[]string{"other"},
+			map[string]interface{}{"internalTags": []string{"other"}},
+		},
+		{
+			"no_key",
+			"",
+			[]string{"other"},
+			map[string]interface{}{},
+		},
+		{
+			"rules",
+			"params.internalTags",
+			[]string{"hide-from-feed", "newsletter-only"},
+			map[string]interface{}{
+				"_build": map[string]interface{}{"list": "never"},
+				"layout": "newsletter",
+				"params": map[string]interface{}{
+					"internalTags": []string{"hide-from-feed", "newsletter-only"},
+					"newsletter":   true,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{internal: tt.internal, rules: rules}
+			metadata := make(map[string]interface{})
+			c.applyInternalTags(metadata, tt.tags)
+			if !reflect.DeepEqual(metadata, tt.want) {
+				t.Errorf("Converter.applyInternalTags() = %v, want %v", metadata, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package ghosttohugo
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// ghostURL is the placeholder Ghost 4+ exports use for the site origin
+const ghostURL = "__GHOST_URL__"
+
+// absoluteURL matches absolute and protocol relative URLs, as well as URLs
+// using the Ghost site placeholder
+var absoluteURL = regexp.MustCompile(
+	`(?i)(?:https?:)?//[^\s"'<>()\[\]\\{}|^` + "`" + `]+|` +
+		ghostURL + `[^\s"'<>()\[\]\\{}|^` + "`" + `]*`,
+)
+
+// origin is a location the Ghost site was served from
+type origin struct {
+	host, path string
+}
+
+func parseOrigin(raw string) origin {
+	raw = strings.TrimSpace(raw)
+	if i := strings.Index(raw, "//"); i >= 0 {
+		raw = raw[i+2:]
+	}
+	raw = strings.TrimRight(raw, "/")
+
+	var o origin
+	o.host = raw
+	if i := strings.Index(raw, "/"); i >= 0 {
+		o.host, o.path = raw[:i], raw[i:]
+	}
+	o.host = strings.TrimPrefix(strings.ToLower(o.host), "www.")
+
+	return o
+}
+
+// relative returns the site relative form of raw when it points at the
+// origin.
+func (o origin) relative(raw string) (string, bool) {
+	i := strings.Index(raw, "//")
+	if i < 0 {
+		return raw, false
+	}
+	rest := raw[i+2:]
+
+	host := rest
+	if j := strings.IndexAny(rest, "/?#"); j >= 0 {
+		host = rest[:j]
+	}
+	if strings.TrimPrefix(strings.ToLower(host), "www.") != o.host {
+		return raw, false
+	}
+
+	p := rest[len(host):]
+	if o.path != "" {
+		if !strings.HasPrefix(p, o.path) {
+			return raw, false
+		}
+		p = p[len(o.path):]
+		if p != "" && strings.IndexAny(p[:1], "/?#") < 0 {
+			return raw, false
+		}
+	}
+
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p, true
+}
+
+func (c Converter) origins() []origin {
+	var origins []origin
+	for _, u := range c.siteURLs {
+		origins = append(origins, parseOrigin(u))
+	}
+	if c.baseURL != "" {
+		origins = append(origins, parseOrigin(c.baseURL))
+	}
+	return origins
+}
+
+// relativeURL returns the site relative form of raw, if it points at the
+// Ghost site or the new Hugo site.
+func (c Converter) relativeURL(raw string) (string, bool) {
+	if strings.HasPrefix(raw, ghostURL) {
+		p := strings.TrimPrefix(raw, ghostURL)
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		return p, true
+	}
+
+	for _, o := range c.origins() {
+		if p, ok := o.relative(raw); ok {
+			return p, true
+		}
+	}
+
+	return raw, false
+}
+
+// rewriteURLs turns every link to the Ghost site found in text into a site
+// relative path, and drops the /content folder from references to uploaded
+// files so they match the layout of the static folder.
+func (c Converter) rewriteURLs(text string) string {
+	text = absoluteURL.ReplaceAllStringFunc(text, func(raw string) string {
+		trimmed := strings.TrimRight(raw, ".,;:!?")
+		p, _ := c.relativeURL(trimmed)
+		return p + raw[len(trimmed):]
+	})
+
+	return replaceAssetRefs(text, stripContentFolder)
+}
+
+// rewriteFrontMatter applies rewriteURLs to every string in the front matter,
+// except the canonical URL, which has to stay absolute. A canonical URL given
+// with the Ghost placeholder points at the new site instead.
+func (c Converter) rewriteFrontMatter(metadata map[string]interface{}) {
+	for key, value := range metadata {
+		if key != "canonical" {
+			metadata[key] = mapStrings(value, c.rewriteURLs)
+			continue
+		}
+		if s, ok := value.(string); ok && strings.HasPrefix(s, ghostURL) {
+			metadata[key] = strings.TrimSuffix(c.siteBaseURL(), "/") + c.contentURL(s)
+		}
+	}
+}
+
+// offsiteURLs returns the absolute URLs in text that are left untouched by
+// rewriteURLs.
+func offsiteURLs(text string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, raw := range absoluteURL.FindAllString(text, -1) {
+		raw = strings.TrimRight(raw, ".,;:!?")
+		if seen[raw] || !strings.HasPrefix(strings.ToLower(raw), "http") {
+			continue
+		}
+		seen[raw] = true
+		urls = append(urls, raw)
+	}
+	return urls
+}
+
+// reportOffsite records the off-site references left in a post
+func (c *Converter) reportOffsite(slug string, texts ...string) {
+	for _, text := range texts {
+		for _, u := range offsiteURLs(text) {
+			c.offsite = append(c.offsite, slug+" "+u)
+			jww.INFO.Printf("%s: off-site reference %s\n", slug, u)
+		}
+	}
+}
+
+// writeOffsite writes the off-site references left in the site to
+// offsite-urls.txt, one per line after the page they are found in.
+func (c *Converter) writeOffsite() error {
+	path := filepath.Join(c.path, "offsite-urls.txt")
+	data := strings.Join(c.offsite, "\n") + "\n"
+	err := helpers.WriteToDisk(path, strings.NewReader(data), c.site.Fs.Source)
+	if err != nil {
+		return err
+	}
+
+	jww.FEEDBACK.Printf(
+		"%d off-site reference(s) left untouched, listed in %s\n",
+		len(c.offsite), path,
+	)
+	return nil
+}
@@ -0,0 +1,156 @@
+package ghosttohugo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+)
+
+func TestConverter_rewriteURLs(t *testing.T) {
+	c := Converter{
+		siteURLs: []string{"https://ourblog.com/", "http://old.example.org/blog"},
+		baseURL:  "https://new.example.com/",
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", ""},
+		{
+			"placeholder",
+			"![](__GHOST_URL__/content/images/a.jpg) [home](__GHOST_URL__)",
+			"![](/images/a.jpg) [home](/)",
+		},
+		{
+			"origin",
+			`{{< figure src="https://www.ourblog.com/content/images/a.jpg" >}}`,
+			`{{< figure src="/images/a.jpg" >}}`,
+		},
+		{
+			"origin_path",
+			"[a](http://old.example.org/blog/a-post/?x=1#y)",
+			"[a](/a-post/?x=1#y)",
+		},
+		{
+			"origin_other_path",
+			"[a](http://old.example.org/blogger/)",
+			"[a](http://old.example.org/blogger/)",
+		},
+		{
+			"base_url",
+			"see https://new.example.com/about/.",
+			"see /about/.",
+		},
+		{
+			"lookalike",
+			"https://ourblog.com.example.net/content/images/a.jpg",
+			"https://ourblog.com.example.net/content/images/a.jpg",
+		},
+		{
+			"offsite",
+			"![](https://images.unsplash.com/photo)",
+			"![](https://images.unsplash.com/photo)",
+		},
+		{
+			"content_folder",
+			"<img src=\"/content/images/a.jpg\">",
+			"<img src=\"/images/a.jpg\">",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.rewriteURLs(tt.text); got != tt.want {
+				t.Errorf("Converter.rewriteURLs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_rewriteFrontMatter(t *testing.T) {
+	c := Converter{siteURLs: []string{"ourblog.com"}}
+	metadata := map[string]interface{}{
+		"image":     "https://ourblog.com/content/images/a.jpg",
+		"canonical": "https://ourblog.com/original/",
+		"draft":     false,
+		"images":    []string{"__GHOST_URL__/content/images/b.jpg"},
+		"params": map[string]interface{}{
+			"og": []interface{}{"https://ourblog.com/content/images/c.jpg"},
+		},
+	}
+	want := map[string]interface{}{
+		"image":     "/images/a.jpg",
+		"canonical": "https://ourblog.com/original/",
+		"draft":     false,
+		"images":    []string{"/images/b.jpg"},
+		"params": map[string]interface{}{
+			"og": []interface{}{"/images/c.jpg"},
+		},
+	}
+
+	c.rewriteFrontMatter(metadata)
+	if !reflect.DeepEqual(metadata, want) {
+		t.Errorf("Converter.rewriteFrontMatter() = %v, want %v", metadata, want)
+	}
+}
+
+func TestConverter_rewriteFrontMatter_canonical(t *testing.T) {
+	c := Converter{
+		baseURL: "https://new.example.com/",
+		pages:   []pageURL{{slug: "a", ghost: "/a/", hugo: "/post/a/"}},
+	}
+	metadata := map[string]interface{}{"canonical": "__GHOST_URL__/a/"}
+
+	c.rewriteFrontMatter(metadata)
+	if got, want := metadata["canonical"], "https://new.example.com/post/a/"; got != want {
+		t.Errorf("Converter.rewriteFrontMatter() canonical = %v, want %v", got, want)
+	}
+}
+
+func Test_offsiteURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"relative", "[a](/a/) // comment", nil},
+		{
+			"absolute",
+			"[a](https://gohugo.io/). See http://example.com, https://gohugo.io/",
+			[]string{"https://gohugo.io/", "http://example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := offsiteURLs(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("offsiteURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_writeOffsite(t *testing.T) {
+	c := &Converter{path: t.TempDir(), kind: metadecoders.TOML}
+	if err := c.createSite(); err != nil {
+		t.Fatal(err)
+	}
+	c.reportOffsite("hello", "[a](https://gohugo.io/) and http://example.com/x")
+	c.reportOffsite("about", "no links")
+	if err := c.writeOffsite(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(c.path, "offsite-urls.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "hello https://gohugo.io/\nhello http://example.com/x\n"
+	if string(data) != want {
+		t.Errorf("offsite-urls.txt = %q, want %q", data, want)
+	}
+}
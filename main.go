@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/jbarone/ghostToHugo/ghosttohugo"
@@ -21,7 +22,21 @@ func main() {
 
 	var (
 		path, loc, format     string
+		baseURL, categories   string
+		internalKey           string
+		internalRules         []string
+		identifierKey         string
+		headPartial           bool
+		permalink             string
+		ampAliases            bool
+		archiveAliases        bool
+		redirects, routes     string
+		paywall               string
+		embeds                []string
+		siteURLs              []string
 		force, verbose, debug bool
+		bundle, fetch         bool
+		fetchLimit            int
 	)
 
 	flag.Usage = usage
@@ -29,11 +44,51 @@ func main() {
 	flag.StringVarP(&path, "hugo", "p", "newhugosite",
 		"path to create the new hugo project")
 	flag.StringVarP(&loc, "location", "l", "",
-		"location to use for time conversions (default: local)")
+		"location to use for time conversions "+
+			"(default: time zone of the Ghost site, or local)")
 	flag.StringVarP(&format, "dateformat", "d", "2006-01-02 15:04:05",
 		"date format string to use for time conversions")
+	flag.StringVarP(&baseURL, "baseurl", "", "",
+		"URL the new Hugo site will be served from")
+	flag.StringSliceVarP(&siteURLs, "url", "u", nil,
+		"URL the Ghost site was served from, links to it become relative")
+	flag.StringVarP(&categories, "categories", "c", "primary",
+		"how categories are picked from tags: primary, none, "+
+			"prefix:<prefix,...> or list:<tag,...>")
+	flag.StringVarP(&internalKey, "internal-tags-key", "", "internalTags",
+		"front matter key internal tags are listed under, empty to leave them out")
+	flag.StringSliceVarP(&internalRules, "internal-tag", "", nil,
+		"behavior of an internal tag as <tag>=hide, <tag>=layout:<name> "+
+			"or <tag>=flag:<key>")
+	flag.StringVarP(&identifierKey, "identifier-key", "", "disqus_identifier",
+		"front matter key the Ghost uuid of a post is written to, empty to leave it out")
+	flag.BoolVarP(&headPartial, "head-partial", "", false,
+		"write a ghost_head.html partial linking canonical URLs and comment identifiers")
+	flag.StringVarP(&permalink, "permalink", "", "",
+		"permalink pattern of the Ghost posts, such as /:year/:month/:slug/ "+
+			"(default: the permalinks setting of the export)")
+	flag.BoolVarP(&ampAliases, "amp-aliases", "", false,
+		"add aliases for the AMP versions of the Ghost posts")
+	flag.BoolVarP(&archiveAliases, "archive-aliases", "", false,
+		"add aliases for the Ghost author archive URLs")
+	flag.StringVarP(&redirects, "redirects", "r", "",
+		"Ghost redirects.json or redirects.yaml file to convert")
+	flag.StringVarP(&routes, "routes", "", "",
+		"Ghost routes.yaml file whose collections become sections")
+	flag.StringVarP(&paywall, "paywall", "", "truncate",
+		"content after the paywall of members only posts: "+
+			"truncate, more (summary divider) or members (shortcode)")
+	flag.StringSliceVarP(&embeds, "embed", "", nil,
+		"shortcode rendering the embeds of a provider (youtube, vimeo, twitter, "+
+			"instagram, gist) as <provider>=<shortcode>, or <provider>=html")
 	flag.BoolVarP(&force, "force", "f", false,
 		"allow import into non-empty target directory")
+	flag.BoolVarP(&bundle, "bundle", "b", false,
+		"write posts as page bundles with their images")
+	flag.BoolVarP(&fetch, "fetch-images", "", false,
+		"download remote images used by posts into the site")
+	flag.IntVarP(&fetchLimit, "fetch-limit", "", 4,
+		"number of images to download at the same time")
 	flag.BoolVarP(&verbose, "verbose", "v", false,
 		"print verbose logging output")
 	flag.BoolVarP(&debug, "debug", "", false,
@@ -65,16 +120,86 @@ func main() {
 		opts = append(opts, ghosttohugo.WithForce())
 	}
 
-	c, err := ghosttohugo.New(opts...)
-	if err != nil {
-		jww.FATAL.Fatalf("Error initializing converter (%v)\n", err)
+	if baseURL != "" {
+		opts = append(opts, ghosttohugo.WithBaseURL(baseURL))
+	}
+
+	if len(siteURLs) > 0 {
+		opts = append(opts, ghosttohugo.WithSiteURLs(siteURLs...))
+	}
+
+	if categories != "" {
+		parts := strings.SplitN(categories, ":", 2)
+		var values []string
+		if len(parts) == 2 {
+			values = strings.Split(parts[1], ",")
+		}
+		opts = append(opts, ghosttohugo.WithCategories(parts[0], values...))
+	}
+
+	opts = append(opts, ghosttohugo.WithInternalTags(internalKey))
+	for _, rule := range internalRules {
+		parts := strings.SplitN(rule, "=", 2)
+		if len(parts) != 2 {
+			jww.FATAL.Fatalf("Invalid internal tag rule %s\n", rule)
+		}
+		behavior := strings.SplitN(parts[1], ":", 2)
+		var value string
+		if len(behavior) == 2 {
+			value = behavior[1]
+		}
+		opts = append(opts, ghosttohugo.WithInternalTagRule(
+			parts[0], behavior[0], value,
+		))
+	}
+
+	opts = append(opts, ghosttohugo.WithIdentifierKey(identifierKey))
+	if headPartial {
+		opts = append(opts, ghosttohugo.WithHeadPartial())
+	}
+
+	if permalink != "" {
+		opts = append(opts, ghosttohugo.WithPermalink(permalink))
+	}
+	if ampAliases {
+		opts = append(opts, ghosttohugo.WithAMPAliases())
+	}
+	if archiveAliases {
+		opts = append(opts, ghosttohugo.WithArchiveAliases())
 	}
 
-	file, err := os.Open(flag.Arg(0))
+	if redirects != "" {
+		opts = append(opts, ghosttohugo.WithRedirects(redirects))
+	}
+
+	if routes != "" {
+		opts = append(opts, ghosttohugo.WithRoutes(routes))
+	}
+
+	if paywall != "" {
+		opts = append(opts, ghosttohugo.WithPaywall(paywall))
+	}
+
+	for _, embed := range embeds {
+		parts := strings.SplitN(embed, "=", 2)
+		if len(parts) != 2 {
+			jww.FATAL.Fatalf("Invalid embed shortcode %s\n", embed)
+		}
+		opts = append(opts, ghosttohugo.WithEmbedShortcode(parts[0], parts[1]))
+	}
+
+	if bundle {
+		opts = append(opts, ghosttohugo.WithBundles())
+	}
+
+	if fetch {
+		opts = append(opts, ghosttohugo.WithFetchImages(fetchLimit))
+	}
+
+	c, err := ghosttohugo.New(opts...)
 	if err != nil {
-		jww.FATAL.Fatalf("Error opening export: %v\n", err)
+		jww.FATAL.Fatalf("Error initializing converter (%v)\n", err)
 	}
-	defer file.Close()
 
 	// setup logging
 	lvl := jww.LevelWarn
@@ -88,7 +213,7 @@ func main() {
 
 	jww.FEEDBACK.Println("Importing...")
 
-	count, err := c.Convert(file)
+	count, err := c.ConvertPath(flag.Arg(0))
 	if err != nil {
 		jww.FATAL.Fatalf("Error opening export: %v\n", err)
 	}
@@ -98,4 +223,8 @@ func main() {
 		"$ git clone https://github.com/spf13/herring-cove.git "+
 		"%s/themes/herring-cove\n", path)
 	jww.FEEDBACK.Printf("$ cd %s\n$ hugo server --theme=herring-cove\n", path)
+	if headPartial {
+		jww.FEEDBACK.Println("Include the Ghost head partial in the <head> " +
+			"of the theme with {{ partial \"ghost_head.html\" . }}")
+	}
 }